@@ -60,6 +60,16 @@ var tests = []struct {
 		directory:   "testdata/pubtypes",
 		args:        []string{"-pubtypes"},
 	},
+	{
+		description: "invoke edgeql-go with -pointers",
+		directory:   "testdata/pointers",
+		args:        []string{"-pointers"},
+	},
+	{
+		description: "invoke edgeql-go with -params",
+		directory:   "testdata/params",
+		args:        []string{"-params"},
+	},
 }
 
 func TestMain(m *testing.M) {
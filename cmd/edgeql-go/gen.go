@@ -36,6 +36,11 @@ func generateType(
 		imports []string
 	)
 
+	if cmdCfg.pointers && !required &&
+		desc.Type != descriptor.Set && desc.Type != descriptor.Array {
+		return generatePointer(desc, path, cmdCfg)
+	}
+
 	switch desc.Type {
 	case descriptor.Set, descriptor.Array:
 		types, imports, err = generateSlice(desc, path, cmdCfg)
@@ -73,6 +78,11 @@ func generateTypeV2(
 		imports []string
 	)
 
+	if cmdCfg.pointers && !required &&
+		desc.Type != descriptor.Set && desc.Type != descriptor.Array {
+		return generatePointerV2(desc, path, cmdCfg)
+	}
+
 	switch desc.Type {
 	case descriptor.Set, descriptor.Array:
 		types, imports, err = generateSliceV2(desc, path, cmdCfg)
@@ -84,6 +94,8 @@ func generateTypeV2(
 		types, imports, err = generateBaseScalarV2(desc, required)
 	case descriptor.Range:
 		types, imports, err = generateRangeV2(desc, required)
+	case descriptor.MultiRange:
+		types, imports, err = generateMultiRangeV2(desc, required)
 	default:
 		err = fmt.Errorf(
 			"generating type: unknown descriptor type %v",
@@ -98,6 +110,43 @@ func generateTypeV2(
 	return types, imports, nil
 }
 
+func generatePointer(
+	desc descriptor.Descriptor,
+	path []string,
+	cmdCfg *cmdConfig,
+) ([]goType, []string, error) {
+	types, imports, err := generateType(desc, true, path, cmdCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return wrapPointer(types), imports, nil
+}
+
+func generatePointerV2(
+	desc *descriptor.V2,
+	path []string,
+	cmdCfg *cmdConfig,
+) ([]goType, []string, error) {
+	types, imports, err := generateTypeV2(desc, true, path, cmdCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return wrapPointer(types), imports, nil
+}
+
+// wrapPointer makes types[0] a pointer
+// unless it is already one like *big.Int.
+func wrapPointer(types []goType) []goType {
+	if strings.HasPrefix(types[0].Reference(), "*") {
+		return types
+	}
+
+	typ := []goType{&goPointer{typ: types[0]}}
+	return append(typ, types...)
+}
+
 func generateRange(
 	desc descriptor.Descriptor,
 	required bool,
@@ -147,35 +196,59 @@ func generateRangeV2(
 		optional = "Optional"
 	}
 
-	var typ string
-	fieldDesc := desc.Fields[0].Desc
+	typ, err := rangeTypeSuffixV2(&desc.Fields[0].Desc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	types := []goType{
+		&goScalar{Name: fmt.Sprintf("edgedb.%sRange%s", optional, typ)},
+	}
+	return types, nil, nil
+}
+
+// generateMultiRangeV2 uses edgedb.Opt for optional multiranges
+// because there are no dedicated optional multirange types.
+func generateMultiRangeV2(
+	desc *descriptor.V2,
+	required bool,
+) ([]goType, []string, error) {
+	typ, err := rangeTypeSuffixV2(&desc.Fields[0].Desc.Fields[0].Desc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := fmt.Sprintf("[]edgedb.Range%s", typ)
+	if !required {
+		name = fmt.Sprintf("edgedb.Opt[%s]", name)
+	}
+
+	return []goType{&goScalar{Name: name}}, nil, nil
+}
+
+func rangeTypeSuffixV2(fieldDesc *descriptor.V2) (string, error) {
 	switch fieldDesc.ID {
 	case codecs.Int32ID:
-		typ = "Int32"
+		return "Int32", nil
 	case codecs.Int64ID:
-		typ = "Int64"
+		return "Int64", nil
 	case codecs.Float32ID:
-		typ = "Float32"
+		return "Float32", nil
 	case codecs.Float64ID:
-		typ = "Float64"
+		return "Float64", nil
 	case codecs.DateTimeID:
-		typ = "DateTime"
+		return "DateTime", nil
 	case codecs.LocalDTID:
-		typ = "LocalDateTime"
+		return "LocalDateTime", nil
 	case codecs.LocalDateID:
-		typ = "LocalDate"
+		return "LocalDate", nil
 	default:
-		return nil, nil, fmt.Errorf(
+		return "", fmt.Errorf(
 			"generating range: unknown %v with id %v",
 			fieldDesc.Type,
 			fieldDesc.ID,
 		)
 	}
-
-	types := []goType{
-		&goScalar{Name: fmt.Sprintf("edgedb.%sRange%s", optional, typ)},
-	}
-	return types, nil, nil
 }
 
 func generateSlice(
@@ -447,6 +520,12 @@ func generateBaseScalar(
 		} else {
 			name = "edgedb.OptionalFloat64"
 		}
+	case codecs.DecimalID:
+		if required {
+			name = "edgedb.Decimal"
+		} else {
+			name = "edgedb.OptionalDecimal"
+		}
 	case codecs.BoolID:
 		if required {
 			name = "bool"
@@ -533,6 +612,57 @@ func generateBaseScalarV2(
 		return []goType{&goScalar{Name: name}}, nil, nil
 	}
 
+	switch desc.Name {
+	case codecs.VectorName, codecs.HalfVectorName:
+		if required {
+			name = "[]float32"
+		} else {
+			name = "edgedb.OptionalVector"
+		}
+
+		return []goType{&goScalar{Name: name}}, nil, nil
+	case codecs.SparseVectorName:
+		if required {
+			name = "edgedb.SparseVector"
+		} else {
+			name = "edgedb.OptionalSparseVector"
+		}
+
+		return []goType{&goScalar{Name: name}}, nil, nil
+	case codecs.GeometryName:
+		if required {
+			name = "edgedb.Geometry"
+		} else {
+			name = "edgedb.OptionalGeometry"
+		}
+
+		return []goType{&goScalar{Name: name}}, nil, nil
+	case codecs.GeographyName:
+		if required {
+			name = "edgedb.Geography"
+		} else {
+			name = "edgedb.OptionalGeography"
+		}
+
+		return []goType{&goScalar{Name: name}}, nil, nil
+	case codecs.Box2DName:
+		if required {
+			name = "edgedb.Box2D"
+		} else {
+			name = "edgedb.OptionalBox2D"
+		}
+
+		return []goType{&goScalar{Name: name}}, nil, nil
+	case codecs.Box3DName:
+		if required {
+			name = "edgedb.Box3D"
+		} else {
+			name = "edgedb.OptionalBox3D"
+		}
+
+		return []goType{&goScalar{Name: name}}, nil, nil
+	}
+
 	var imports []string
 	switch desc.ID {
 	case codecs.UUIDID:
@@ -583,6 +713,12 @@ func generateBaseScalarV2(
 		} else {
 			name = "edgedb.OptionalFloat64"
 		}
+	case codecs.DecimalID:
+		if required {
+			name = "edgedb.Decimal"
+		} else {
+			name = "edgedb.OptionalDecimal"
+		}
 	case codecs.BoolID:
 		if required {
 			name = "bool"
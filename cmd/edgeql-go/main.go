@@ -62,6 +62,8 @@ type cmdConfig struct {
 	mixedCaps bool
 	pubfuncs  bool
 	pubtypes  bool
+	pointers  bool
+	params    bool
 }
 
 func main() {
@@ -76,12 +78,19 @@ func main() {
 		"Make generated functions public.")
 	pubtypes := flag.Bool("pubtypes", false,
 		"Make generated types public.")
+	pointers := flag.Bool("pointers", false,
+		"Use pointers instead of optional types "+
+			"for values that are not required.")
+	params := flag.Bool("params", false,
+		"Pass query arguments to generated functions as a struct.")
 	flag.Parse()
 
 	cfg := &cmdConfig{
 		mixedCaps: *mixedCaps,
 		pubfuncs:  *pubfuncs,
 		pubtypes:  *pubtypes,
+		pointers:  *pointers,
+		params:    *params,
 	}
 
 	timer := time.AfterFunc(200*time.Millisecond, func() {
@@ -45,6 +45,7 @@ type queryConfig struct {
 	imports []string
 	structs []*goStruct
 	sTypes  *goStruct
+	pType   *goStruct
 	rTypes  []goType
 }
 
@@ -107,6 +108,7 @@ func newQuery(
 		ResultTypes:         q.structs,
 		SignatureReturnType: q.rTypes[0].Reference(),
 		SignatureArgs:       q.sTypes.Fields,
+		ParamsType:          q.pType,
 		Method:              q.method,
 	}, nil
 }
@@ -148,6 +150,7 @@ func (r *queryConfigV1) setup(
 		log.Fatal(err)
 	}
 	imports = append(imports, i...)
+	pType := paramsType(qryFile, qryName, sTypes, cmdCfg)
 
 	qryFile, err = queryFile(outFile, qryFile)
 	if err != nil {
@@ -166,6 +169,7 @@ func (r *queryConfigV1) setup(
 		imports: imports,
 		structs: rStructs,
 		sTypes:  sTypes,
+		pType:   pType,
 		rTypes:  rTypes,
 	}, nil
 }
@@ -207,6 +211,7 @@ func (r *queryConfigV2) setup(
 		log.Fatal(err)
 	}
 	imports = append(imports, i...)
+	pType := paramsType(qryFile, qryName, sTypes, cmdCfg)
 
 	qryFile, err = queryFile(outFile, qryFile)
 	if err != nil {
@@ -225,6 +230,7 @@ func (r *queryConfigV2) setup(
 		imports: imports,
 		structs: rStructs,
 		sTypes:  sTypes,
+		pType:   pType,
 		rTypes:  rTypes,
 	}, nil
 }
@@ -283,6 +289,33 @@ func signatureTypesV2(
 	return types[0].(*goStruct), imports, nil
 }
 
+// paramsType returns the struct that is generated for the query's arguments
+// when the -params option is used. It returns nil if the query has no
+// arguments.
+func paramsType(
+	qryFile,
+	qryName string,
+	sTypes *goStruct,
+	cmdCfg *cmdConfig,
+) *goStruct {
+	if !cmdCfg.params || len(sTypes.Fields) == 0 {
+		return nil
+	}
+
+	typ := &goStruct{
+		Name:          typeName(qryFile, cmdCfg) + "Params",
+		QueryFuncName: qryName,
+		Required:      true,
+	}
+
+	for _, field := range sTypes.Fields {
+		field.GoName = snakeToUpperMixedCase(field.EQLName)
+		typ.Fields = append(typ.Fields, field)
+	}
+
+	return typ
+}
+
 func resultTypes(
 	qryFile string,
 	description *edgedb.CommandDescription,
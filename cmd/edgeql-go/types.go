@@ -24,6 +24,7 @@ type Query struct {
 	ResultTypes         []*goStruct
 	SignatureReturnType string
 	SignatureArgs       []goStructField
+	ParamsType          *goStruct
 	Method              string
 
 	imports []string
@@ -46,6 +47,12 @@ type goSlice struct {
 
 func (t *goSlice) Reference() string { return "[]" + t.typ.Reference() }
 
+type goPointer struct {
+	typ goType
+}
+
+func (t *goPointer) Reference() string { return "*" + t.typ.Reference() }
+
 type goStructField struct {
 	EQLName string
 	GoName  string
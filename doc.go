@@ -77,6 +77,10 @@
 //	err := client.Query(...)
 //	if errors.Is(err, context.Canceled) { ... }
 //
+// Queries that are running when their context is canceled or its deadline
+// passes are interrupted. The query's connection is closed and a new one is
+// opened the next time the client needs it.
+//
 // Most errors returned by the edgedb package will satisfy the edgedb.Error
 // interface which has methods for introspecting.
 //
@@ -119,8 +123,20 @@
 //	uuid                     edgedb.UUID, edgedb.OptionalUUID
 //	json                     []byte, edgedb.OptionalBytes
 //	bigint                   *big.Int, edgedb.OptionalBigInt
-//
-//	decimal                  user defined (see Custom Marshalers)
+//	decimal                  edgedb.Decimal, edgedb.OptionalDecimal
+//	ext::pgvector::vector    []float32, edgedb.OptionalVector
+//	ext::pgvector::halfvec   []float32, edgedb.OptionalVector
+//	ext::pgvector::sparsevec edgedb.SparseVector,
+//	                         edgedb.OptionalSparseVector
+//	ext::postgis::geometry   edgedb.Geometry, edgedb.OptionalGeometry
+//	ext::postgis::geography  edgedb.Geography, edgedb.OptionalGeography
+//	ext::postgis::box2d      edgedb.Box2D, edgedb.OptionalBox2D
+//	ext::postgis::box3d      edgedb.Box3D, edgedb.OptionalBox3D
+//
+// Vector and PostGIS types are only supported with EdgeDB 5.0 or newer.
+// PostGIS values are stored as well-known binary. Geometry and Geography
+// can be converted to and from well-known text and the Point, LineString
+// and Polygon types.
 //
 // Note that EdgeDB's std::duration type is represented in int64 microseconds
 // while go's time.Duration type is int64 nanoseconds. It is incorrect to cast
@@ -144,6 +160,24 @@
 //	fmt.Println(result.Missing())
 //	// Output: false
 //
+// Pointers can be used instead of optional types. A missing value is decoded
+// as nil and a nil argument is sent as a missing value.
+//
+//	type User struct {
+//	    Email *string `edgedb:"email"`
+//	}
+//
+//	var result *User
+//	err := client.QuerySingle(ctx, `SELECT User { email } LIMIT 1`, &result)
+//
+// edgedb.Opt is a generic optional type that can hold any type that can be
+// decoded, including structs and types with custom unmarshalers.
+//
+//	type User struct {
+//	    Email edgedb.Opt[string] `edgedb:"email"`
+//	    Boss  edgedb.Opt[Person] `edgedb:"boss"`
+//	}
+//
 // Not all types listed above are valid query parameters.  To pass a slice of
 // scalar values use array in your query. EdgeDB doesn't currently support
 // using sets as parameters.
@@ -154,6 +188,20 @@
 // Nested structures are also not directly allowed but you can use [json]
 // instead.
 //
+// Named arguments are passed as a map[string]interface{} or as a struct
+// with an exported field for every argument. Struct fields are matched to
+// arguments the same way shape fields are matched when decoding results.
+// A struct field that does not match any argument is an error.
+//
+//	type Args struct {
+//	    Name  string             `edgedb:"name"`
+//	    Email edgedb.Opt[string] `edgedb:"email"`
+//	}
+//
+//	query := `select User filter .name = <str>$name
+//	    and .email ?= <optional str>$email`
+//	err := client.Query(ctx, query, &users, Args{Name: "Alice"})
+//
 // By default EdgeDB will ignore embedded structs when marshaling/unmarshaling.
 // To treat an embedded struct's fields as part of the parent struct's fields,
 // tag the embedded struct with `edgedb:"$inline"`.
@@ -167,11 +215,76 @@
 //	    Name string
 //	}
 //
+// # Dynamic Results
+//
+// Results can be decoded without declaring a go type for their shape by
+// querying into interface{}, map[string]interface{} or edgedb.Object.
+// Scalars are decoded into the types listed above, sets, arrays and tuples
+// into []interface{} and json into the value returned by json.Unmarshal.
+//
+//	var users []map[string]interface{}
+//	err := client.Query(ctx, `SELECT User { name, friends: { name } }`, &users)
+//
+// Objects nested inside of a map are also maps. Maps do not include implicit
+// fields like id. edgedb.Object keeps every field in the order the server
+// sent them and records whether each field is implicit or a link property.
+// Objects nested inside of an edgedb.Object or an interface{} are decoded
+// as edgedb.Object.
+//
+//	var user edgedb.Object
+//	err := client.QuerySingle(ctx, `SELECT User { name } LIMIT 1`, &user)
+//	name, ok := user.Get("name")
+//
+// Dynamic results require EdgeDB 5.0 or newer.
+//
+// # database/sql
+//
+// Importing this package registers a database/sql driver named "edgedb".
+// The data source name is the same as the dsn argument to CreateClientDSN.
+// Use NewConnector and sql.OpenDB to pass Options.
+//
+//	db, err := sql.Open("edgedb", "edgedb://edgedb@localhost/test")
+//	rows, err := db.QueryContext(ctx, "SELECT User { name, age }")
+//
+// Objects and named tuples have one column per field.
+// Other results have a single column named result.
+// Numbers, strings, bytes, booleans and datetimes are returned as is,
+// other scalars as strings and collections and objects as JSON.
+// Arguments are positional unless they are all sql.Named.
+// Transactions support the serializable and repeatable read isolation levels.
+// Queries use dynamic results and require EdgeDB 5.0 or newer.
+//
+// Types like UUID, LocalDate, Duration, Memory, the Optional types and Opt
+// implement sql.Scanner and driver.Valuer so they can be used as arguments
+// and scanned from results with this or any other database/sql driver.
+// They are passed as text unless database/sql has an equivalent type,
+// and missing optional values are NULL. Ranges use the postgres range
+// literal format like [1,5), vectors use the pgvector text format and
+// PostGIS types are hex encoded well-known binary. Multiranges are slices
+// of ranges so they do not implement either interface.
+//
 // # Custom Marshalers
 //
 // Interfaces for user defined marshaler/unmarshalers  are documented in the
 // internal/marshal package.
 //
+// RegisterCodec maps a scalar type to a go type without implementing the
+// marshaler interfaces, for example to use a decimal package or to store
+// netip.Addr values in a scalar type that extends str.
+//
+//	func init() {
+//	    edgedb.RegisterCodec(
+//	        "default::ip_addr",
+//	        reflect.TypeOf(netip.Addr{}),
+//	        func(v interface{}) ([]byte, error) {
+//	            return []byte(v.(netip.Addr).String()), nil
+//	        },
+//	        func(data []byte) (interface{}, error) {
+//	            return netip.ParseAddr(string(data))
+//	        },
+//	    )
+//	}
+//
 // [EdgeDB]: https://www.edgedb.com
 // [json]: https://www.edgedb.com/docs/edgeql/insert#bulk-inserts
 // [client connection docs]: https://www.edgedb.com/docs/clients/connection
@@ -29,7 +29,19 @@ const (
 	// by a network error.
 	NetworkError = edgedb.NetworkError
 
-	// Serializable is the only isolation level
+	// PreferRepeatableRead uses RepeatableRead and falls back to
+	// Serializable if the server reports that a statement is not supported
+	// in repeatable read transactions. Tx() runs the transaction again with
+	// Serializable when any of its statements fails this way, transactions
+	// started with Client.BeginTx() only fall back when they are started.
+	PreferRepeatableRead = edgedb.PreferRepeatableRead
+
+	// RepeatableRead avoids serialization conflicts between transactions
+	// that only read data. Only concurrent writes to the same data conflict.
+	// RepeatableRead requires EdgeDB 6.0 or newer.
+	RepeatableRead = edgedb.RepeatableRead
+
+	// Serializable is the default isolation level.
 	Serializable = edgedb.Serializable
 
 	// TLSModeDefault makes security mode inferred from other options
@@ -47,17 +59,59 @@ const (
 
 	// TxConflict indicates that the server could not complete a transaction
 	// because it encountered a deadlock or serialization error.
+	// Use RetryOptions.WithIsolation() to retry conflicts differently
+	// depending on the transaction's isolation level.
 	TxConflict = edgedb.TxConflict
 )
 
 type (
+	// AcquireEvent describes acquiring a connection from the pool.
+	AcquireEvent = edgedb.AcquireEvent
+
+	// Batch is a list of queries that are sent to the server together
+	// in a single round trip. The zero value is an empty batch ready to use.
+	// Queries are queued with Execute(), Query() and QuerySingle()
+	// and then run with Client.Batch() or Tx.Batch().
+	// The out arguments are not written to until the batch is run.
+	// Queries that are not in the client's query cache are parsed before
+	// the batch is sent, which takes an extra round trip for each of them.
+	Batch = edgedb.Batch
+
+	// Box2D is an ext::postgis::box2d value. The server sends boxes
+	// as the well-known binary of the equivalent geometry.
+	Box2D = edgedbtypes.Box2D
+
+	// Box3D is an ext::postgis::box3d value. The server sends boxes
+	// as the well-known binary of the equivalent geometry.
+	Box3D = edgedbtypes.Box3D
+
 	// Client is a connection pool and is safe for concurrent use.
 	Client = edgedb.Client
 
+	// ClientStats is a snapshot of a Client's connection pool metrics.
+	// Counters are cumulative over the lifetime of the Client.
+	ClientStats = edgedb.ClientStats
+
+	// CodecDecodeFunc decodes data from the binary wire format of the scalar
+	// type it was registered for. The returned value must be assignable to the
+	// go type it was registered for.
+	CodecDecodeFunc = edgedb.CodecDecodeFunc
+
+	// CodecEncodeFunc encodes value into the binary wire format
+	// of the scalar type it was registered for.
+	CodecEncodeFunc = edgedb.CodecEncodeFunc
+
 	// DateDuration represents the elapsed time between two dates in a fuzzy human
 	// way.
 	DateDuration = edgedbtypes.DateDuration
 
+	// Decimal is an exact arbitrary precision decimal number.
+	// It is represented as an unscaled integer and a scale, the number of
+	// digits after the decimal point. The zero value is 0.
+	//
+	// Decimals are immutable, methods never change their receiver.
+	Decimal = edgedbtypes.Decimal
+
 	// Duration represents the elapsed time between two instants
 	// as an int64 microsecond count.
 	Duration = edgedbtypes.Duration
@@ -75,10 +129,19 @@ type (
 	// that can run queries on an EdgeDB database.
 	Executor = edgedb.Executor
 
+	// Geography is an ext::postgis::geography value.
+	Geography = edgedbtypes.Geography
+
+	// Geometry is an ext::postgis::geometry value.
+	Geometry = edgedbtypes.Geometry
+
 	// IsolationLevel documentation can be found here
 	// https://www.edgedb.com/docs/reference/edgeql/tx_start#parameters
 	IsolationLevel = edgedb.IsolationLevel
 
+	// LineString is a sequence of connected points.
+	LineString = edgedbtypes.LineString
+
 	// LocalDate is a date without a time zone.
 	// https://www.edgedb.com/docs/stdlib/datetime#type::cal::local_date
 	LocalDate = edgedbtypes.LocalDate
@@ -91,12 +154,25 @@ type (
 	// https://www.edgedb.com/docs/stdlib/datetime#type::cal::local_time
 	LocalTime = edgedbtypes.LocalTime
 
+	// Logger receives the client's log messages. args are alternating keys and
+	// values that add structured attributes to the message. *slog.Logger
+	// implements Logger.
+	Logger = edgedb.Logger
+
 	// Memory represents memory in bytes.
 	Memory = edgedbtypes.Memory
 
 	// ModuleAlias is an alias name and module name pair.
 	ModuleAlias = edgedb.ModuleAlias
 
+	// Object is a dynamically decoded object or named tuple.
+	// It is used when the shape of a query's result is not known ahead of time.
+	// Fields are kept in the order the server returned them.
+	Object = edgedbtypes.Object
+
+	// ObjectField is a field of an Object.
+	ObjectField = edgedbtypes.ObjectField
+
 	// Optional represents a shape field that is not required.
 	// Optional is embedded in structs to make them optional. For example:
 	//
@@ -114,6 +190,14 @@ type (
 	// parameters when a shape field is not required.
 	OptionalBool = edgedbtypes.OptionalBool
 
+	// OptionalBox2D is an optional Box2D. Optional types must be used for out
+	// parameters when a shape field is not required.
+	OptionalBox2D = edgedbtypes.OptionalBox2D
+
+	// OptionalBox3D is an optional Box3D. Optional types must be used for out
+	// parameters when a shape field is not required.
+	OptionalBox3D = edgedbtypes.OptionalBox3D
+
 	// OptionalBytes is an optional []byte. Optional types must be used for out
 	// parameters when a shape field is not required.
 	OptionalBytes = edgedbtypes.OptionalBytes
@@ -126,6 +210,10 @@ type (
 	// out parameters when a shape field is not required.
 	OptionalDateTime = edgedbtypes.OptionalDateTime
 
+	// OptionalDecimal is an optional Decimal. Optional types must be used for
+	// out parameters when a shape field is not required.
+	OptionalDecimal = edgedbtypes.OptionalDecimal
+
 	// OptionalDuration is an optional Duration. Optional types must be used for
 	// out parameters when a shape field is not required.
 	OptionalDuration = edgedbtypes.OptionalDuration
@@ -138,6 +226,14 @@ type (
 	// parameters when a shape field is not required.
 	OptionalFloat64 = edgedbtypes.OptionalFloat64
 
+	// OptionalGeography is an optional Geography. Optional types must be used for
+	// out parameters when a shape field is not required.
+	OptionalGeography = edgedbtypes.OptionalGeography
+
+	// OptionalGeometry is an optional Geometry. Optional types must be used for
+	// out parameters when a shape field is not required.
+	OptionalGeometry = edgedbtypes.OptionalGeometry
+
 	// OptionalInt16 is an optional int16. Optional types must be used for out
 	// parameters when a shape field is not required.
 	OptionalInt16 = edgedbtypes.OptionalInt16
@@ -198,6 +294,10 @@ type (
 	// must be used for out parameters when a shape field is not required.
 	OptionalRelativeDuration = edgedbtypes.OptionalRelativeDuration
 
+	// OptionalSparseVector is an optional SparseVector. Optional types must be
+	// used for out parameters when a shape field is not required.
+	OptionalSparseVector = edgedbtypes.OptionalSparseVector
+
 	// OptionalStr is an optional string. Optional types must be used for out
 	// parameters when a shape field is not required.
 	OptionalStr = edgedbtypes.OptionalStr
@@ -206,9 +306,25 @@ type (
 	// parameters when a shape field is not required.
 	OptionalUUID = edgedbtypes.OptionalUUID
 
+	// OptionalVector is an optional ext::pgvector::vector or
+	// ext::pgvector::halfvec. Optional types must be used for out parameters
+	// when a shape field is not required.
+	OptionalVector = edgedbtypes.OptionalVector
+
 	// Options for connecting to an EdgeDB server
 	Options = edgedb.Options
 
+	// Point is a two dimensional point.
+	Point = edgedbtypes.Point
+
+	// Polygon is a sequence of linear rings. The first ring is the exterior
+	// boundary and the rest are holes. Each ring's last point must equal its
+	// first point.
+	Polygon = edgedbtypes.Polygon
+
+	// QueryEvent describes parsing or running a query.
+	QueryEvent = edgedb.QueryEvent
+
 	// RangeDateTime is an interval of time.Time values.
 	RangeDateTime = edgedbtypes.RangeDateTime
 
@@ -242,15 +358,45 @@ type (
 	// run in Tx() methods to be retried.
 	RetryCondition = edgedb.RetryCondition
 
+	// RetryEvent describes a retry of a failed query or transaction.
+	RetryEvent = edgedb.RetryEvent
+
+	// RetryObserver is called each time a query or a transaction
+	// is about to be retried.
+	// attempt is the number of the attempt that failed with err
+	// and wait is how long the client waits before the next attempt.
+	RetryObserver = edgedb.RetryObserver
+
 	// RetryOptions configures how Tx() retries failed transactions.  Use
 	// NewRetryOptions to get a default RetryOptions value instead of creating one
 	// yourself.
 	RetryOptions = edgedb.RetryOptions
 
+	// RetryPredicate reports whether a failed query or transaction
+	// should be retried. See RetryOptions.WithPredicate().
+	RetryPredicate = edgedb.RetryPredicate
+
 	// RetryRule determines how transactions should be retried when run in Tx()
 	// methods. See Client.Tx() for details.
 	RetryRule = edgedb.RetryRule
 
+	// Rows is an iterator over the results of a query.
+	// Use Client.QueryIter() or Tx.QueryIter() to get Rows.
+	//
+	// Results are decoded one at a time as they are read from the network
+	// so the whole result set never needs to be held in memory.
+	// The connection used by Rows is not available for other queries
+	// until Next returns false or Close is called.
+	Rows = edgedb.Rows
+
+	// Shape is a two dimensional geometric shape.
+	// It is implemented by Point, LineString and Polygon.
+	Shape = edgedbtypes.Shape
+
+	// SparseVector is an ext::pgvector::sparsevec.
+	// Only the non zero elements are stored.
+	SparseVector = edgedbtypes.SparseVector
+
 	// TLSOptions contains the parameters needed to configure TLS on EdgeDB
 	// server connections.
 	TLSOptions = edgedb.TLSOptions
@@ -258,12 +404,24 @@ type (
 	// TLSSecurityMode specifies how strict TLS validation is.
 	TLSSecurityMode = edgedb.TLSSecurityMode
 
-	// Tx is a transaction. Use Client.Tx() to get a transaction.
+	// Tracer observes the work done by a Client. Events are reported after the
+	// work is finished. Each event records when the work started and how long
+	// it took so that it can be recorded as a span by a tracing library.
+	//
+	// Tracer methods are called synchronously while the connection is held
+	// and must be safe for concurrent use.
+	Tracer = edgedb.Tracer
+
+	// Tx is a transaction. Use Client.Tx() or Client.BeginTx()
+	// to get a transaction.
 	Tx = edgedb.Tx
 
 	// TxBlock is work to be done in a transaction.
 	TxBlock = edgedb.TxBlock
 
+	// TxEvent describes a transaction control command.
+	TxEvent = edgedb.TxEvent
+
 	// TxOptions configures how transactions behave.
 	TxOptions = edgedb.TxOptions
 
@@ -297,12 +455,64 @@ var (
 	// from a [time.Duration] represented as nanoseconds.
 	DurationFromNanoseconds = edgedbtypes.DurationFromNanoseconds
 
+	// IsRetryable returns true if err indicates that a failed transaction
+	// might succeed if it is run again. Client.Tx() retries these errors
+	// automatically, transactions started with Client.BeginTx() are not retried.
+	IsRetryable = edgedb.IsRetryable
+
 	// LogWarnings is an edgedb.WarningHandler that logs warnings.
 	LogWarnings = edgedb.LogWarnings
 
+	// NewBox2DFromWKB returns a Box2D holding a copy of wkb.
+	// wkb is not validated.
+	NewBox2DFromWKB = edgedbtypes.NewBox2DFromWKB
+
+	// NewBox3DFromWKB returns a Box3D holding a copy of wkb.
+	// wkb is not validated.
+	NewBox3DFromWKB = edgedbtypes.NewBox3DFromWKB
+
+	// NewConnector returns a database/sql/driver.Connector
+	// for use with sql.OpenDB(). The dsn and opts arguments
+	// are the same as for CreateClientDSN().
+	//
+	// Each database/sql connection is a single EdgeDB connection.
+	// Query results are decoded dynamically, so querying requires
+	// EdgeDB server version 5.0 or greater.
+	NewConnector = edgedb.NewConnector
+
 	// NewDateDuration returns a new DateDuration
 	NewDateDuration = edgedbtypes.NewDateDuration
 
+	// NewDecimal returns a Decimal with the value unscaled * 10**-scale.
+	// A negative scale multiplies unscaled by a power of ten.
+	NewDecimal = edgedbtypes.NewDecimal
+
+	// NewDecimalFromFloat64 returns the Decimal with the fewest digits
+	// that converts back to f. It returns an error if f is NaN or infinite.
+	NewDecimalFromFloat64 = edgedbtypes.NewDecimalFromFloat64
+
+	// NewDecimalFromInt64 returns a Decimal with the value i.
+	NewDecimalFromInt64 = edgedbtypes.NewDecimalFromInt64
+
+	// NewDecimalFromRat returns a Decimal with the value r.
+	// It returns an error if r can not be written
+	// with a finite number of decimal digits, for example 1/3.
+	NewDecimalFromRat = edgedbtypes.NewDecimalFromRat
+
+	// NewGeography returns the Geography for shape.
+	NewGeography = edgedbtypes.NewGeography
+
+	// NewGeographyFromWKB returns a Geography holding a copy of wkb.
+	// wkb is not validated.
+	NewGeographyFromWKB = edgedbtypes.NewGeographyFromWKB
+
+	// NewGeometry returns the Geometry for shape.
+	NewGeometry = edgedbtypes.NewGeometry
+
+	// NewGeometryFromWKB returns a Geometry holding a copy of wkb.
+	// wkb is not validated.
+	NewGeometryFromWKB = edgedbtypes.NewGeometryFromWKB
+
 	// NewLocalDate returns a new LocalDate
 	NewLocalDate = edgedbtypes.NewLocalDate
 
@@ -320,6 +530,14 @@ var (
 	// its value set to v.
 	NewOptionalBool = edgedbtypes.NewOptionalBool
 
+	// NewOptionalBox2D is a convenience function for creating an
+	// OptionalBox2D with its value set to v.
+	NewOptionalBox2D = edgedbtypes.NewOptionalBox2D
+
+	// NewOptionalBox3D is a convenience function for creating an
+	// OptionalBox3D with its value set to v.
+	NewOptionalBox3D = edgedbtypes.NewOptionalBox3D
+
 	// NewOptionalBytes is a convenience function for creating an OptionalBytes
 	// with its value set to v.
 	NewOptionalBytes = edgedbtypes.NewOptionalBytes
@@ -332,6 +550,10 @@ var (
 	// OptionalDateTime with its value set to v.
 	NewOptionalDateTime = edgedbtypes.NewOptionalDateTime
 
+	// NewOptionalDecimal is a convenience function for creating an
+	// OptionalDecimal with its value set to v.
+	NewOptionalDecimal = edgedbtypes.NewOptionalDecimal
+
 	// NewOptionalDuration is a convenience function for creating an
 	// OptionalDuration with its value set to v.
 	NewOptionalDuration = edgedbtypes.NewOptionalDuration
@@ -344,6 +566,14 @@ var (
 	// with its value set to v.
 	NewOptionalFloat64 = edgedbtypes.NewOptionalFloat64
 
+	// NewOptionalGeography is a convenience function for creating an
+	// OptionalGeography with its value set to v.
+	NewOptionalGeography = edgedbtypes.NewOptionalGeography
+
+	// NewOptionalGeometry is a convenience function for creating an
+	// OptionalGeometry with its value set to v.
+	NewOptionalGeometry = edgedbtypes.NewOptionalGeometry
+
 	// NewOptionalInt16 is a convenience function for creating an OptionalInt16
 	// with its value set to v.
 	NewOptionalInt16 = edgedbtypes.NewOptionalInt16
@@ -404,6 +634,10 @@ var (
 	// OptionalRelativeDuration with its value set to v.
 	NewOptionalRelativeDuration = edgedbtypes.NewOptionalRelativeDuration
 
+	// NewOptionalSparseVector is a convenience function for creating an
+	// OptionalSparseVector with its value set to v.
+	NewOptionalSparseVector = edgedbtypes.NewOptionalSparseVector
+
 	// NewOptionalStr is a convenience function for creating an OptionalStr with
 	// its value set to v.
 	NewOptionalStr = edgedbtypes.NewOptionalStr
@@ -412,6 +646,10 @@ var (
 	// its value set to v.
 	NewOptionalUUID = edgedbtypes.NewOptionalUUID
 
+	// NewOptionalVector is a convenience function for creating an
+	// OptionalVector with its value set to v.
+	NewOptionalVector = edgedbtypes.NewOptionalVector
+
 	// NewRangeDateTime creates a new RangeDateTime value.
 	NewRangeDateTime = edgedbtypes.NewRangeDateTime
 
@@ -442,12 +680,74 @@ var (
 	// NewRetryRule returns the default RetryRule value.
 	NewRetryRule = edgedb.NewRetryRule
 
+	// NewSparseVector returns a SparseVector with dim dimensions.
+	// elements maps zero based indices to values. Zero values are dropped.
+	NewSparseVector = edgedbtypes.NewSparseVector
+
+	// NewSparseVectorFromSlice returns a SparseVector
+	// with the same elements as vec.
+	NewSparseVectorFromSlice = edgedbtypes.NewSparseVectorFromSlice
+
 	// NewTxOptions returns the default TxOptions value.
 	NewTxOptions = edgedb.NewTxOptions
 
+	// NormalizeMultiRangeDateTime returns the ranges in m sorted with overlapping
+	// and adjacent ranges merged and empty ranges removed.
+	NormalizeMultiRangeDateTime = edgedbtypes.NormalizeMultiRangeDateTime
+
+	// NormalizeMultiRangeFloat32 returns the ranges in m sorted with overlapping
+	// and adjacent ranges merged and empty ranges removed.
+	NormalizeMultiRangeFloat32 = edgedbtypes.NormalizeMultiRangeFloat32
+
+	// NormalizeMultiRangeFloat64 returns the ranges in m sorted with overlapping
+	// and adjacent ranges merged and empty ranges removed.
+	NormalizeMultiRangeFloat64 = edgedbtypes.NormalizeMultiRangeFloat64
+
+	// NormalizeMultiRangeInt32 returns the ranges in m sorted with overlapping
+	// and adjacent ranges merged and empty ranges removed.
+	NormalizeMultiRangeInt32 = edgedbtypes.NormalizeMultiRangeInt32
+
+	// NormalizeMultiRangeInt64 returns the ranges in m sorted with overlapping
+	// and adjacent ranges merged and empty ranges removed.
+	NormalizeMultiRangeInt64 = edgedbtypes.NormalizeMultiRangeInt64
+
+	// NormalizeMultiRangeLocalDate returns the ranges in m sorted with overlapping
+	// and adjacent ranges merged and empty ranges removed.
+	NormalizeMultiRangeLocalDate = edgedbtypes.NormalizeMultiRangeLocalDate
+
+	// NormalizeMultiRangeLocalDateTime returns the ranges in m sorted with
+	// overlapping and adjacent ranges merged and empty ranges removed.
+	NormalizeMultiRangeLocalDateTime = edgedbtypes.NormalizeMultiRangeLocalDateTime
+
+	// ParseDecimal parses a decimal number like -12.340 or 1.5e3.
+	// The scale of the result is the number of digits after the decimal point.
+	ParseDecimal = edgedbtypes.ParseDecimal
+
+	// ParseGeography parses a POINT, LINESTRING or POLYGON from well-known text.
+	ParseGeography = edgedbtypes.ParseGeography
+
+	// ParseGeometry parses a POINT, LINESTRING or POLYGON from well-known text.
+	ParseGeometry = edgedbtypes.ParseGeometry
+
 	// ParseUUID parses s into a UUID or returns an error.
 	ParseUUID = edgedbtypes.ParseUUID
 
+	// RegisterCodec makes encode and decode the codec for goType values
+	// of the scalar type named typeName, for example std::str, cal::local_date,
+	// ext::pgvector::vector or default::my_scalar. Registered codecs are used
+	// before the built in codecs. A codec registered for a scalar type is also
+	// used for scalar types that extend it unless they have their own codec.
+	//
+	// Types that are not built in are matched by name only with EdgeDB 5.0
+	// or newer. If goType's pointer implements SetMissing(bool) it can be used
+	// for optional shape fields, and if goType implements Missing() bool
+	// it can be used for optional arguments.
+	//
+	// RegisterCodec should be called before any queries are run, usually in
+	// an init function. It panics if typeName is empty, if goType, encode or
+	// decode is nil, or if it is called twice for the same typeName and goType.
+	RegisterCodec = edgedb.RegisterCodec
+
 	// WarningsAsErrors is an edgedb.WarningHandler that returns warnings as
 	// errors.
 	WarningsAsErrors = edgedb.WarningsAsErrors
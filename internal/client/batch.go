@@ -0,0 +1,147 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import "context"
+
+// Batch is a list of queries that are sent to the server together
+// in a single round trip. The zero value is an empty batch ready to use.
+// Queries are queued with Execute(), Query() and QuerySingle()
+// and then run with Client.Batch() or Tx.Batch().
+// The out arguments are not written to until the batch is run.
+// Queries that are not in the client's query cache are parsed before
+// the batch is sent, which takes an extra round trip for each of them.
+type Batch struct {
+	items []batchItem
+}
+
+type batchItem struct {
+	method string
+	cmd    string
+	out    interface{}
+	args   []interface{}
+}
+
+// Execute queues an EdgeQL command (or commands).
+func (b *Batch) Execute(cmd string, args ...interface{}) {
+	b.items = append(b.items, batchItem{
+		method: "Execute",
+		cmd:    cmd,
+		args:   args,
+	})
+}
+
+// Query queues a query. The results are written to out
+// when the batch is run.
+func (b *Batch) Query(cmd string, out interface{}, args ...interface{}) {
+	b.items = append(b.items, batchItem{
+		method: "Query",
+		cmd:    cmd,
+		out:    out,
+		args:   args,
+	})
+}
+
+// QuerySingle queues a singleton-returning query. The result is written to
+// out when the batch is run. If the query doesn't return a result
+// a NoDataError is returned unless out is an optional type.
+func (b *Batch) QuerySingle(
+	cmd string,
+	out interface{},
+	args ...interface{},
+) {
+	b.items = append(b.items, batchItem{
+		method: "QuerySingle",
+		cmd:    cmd,
+		out:    out,
+		args:   args,
+	})
+}
+
+// Len returns the number of queued queries.
+func (b *Batch) Len() int {
+	return len(b.items)
+}
+
+type batchable interface {
+	capabilities1pX() uint64
+	batchFlow(context.Context, []*query) error
+}
+
+func runBatch(
+	ctx context.Context,
+	c batchable,
+	b *Batch,
+	state map[string]interface{},
+	warningHandler WarningHandler,
+	tracer Tracer,
+) error {
+	if len(b.items) == 0 {
+		return nil
+	}
+
+	qs := make([]*query, len(b.items))
+	for i, item := range b.items {
+		q, err := newQuery(
+			item.method,
+			item.cmd,
+			item.args,
+			c.capabilities1pX(),
+			state,
+			item.out,
+			true,
+			warningHandler,
+			tracer,
+		)
+		if err != nil {
+			return err
+		}
+
+		qs[i] = q
+	}
+
+	return c.batchFlow(ctx, qs)
+}
+
+// Batch runs all of the queries in b in a single round trip.
+// Queries are run in order. If a query fails the queries after it
+// are skipped. Use Tx.Batch() when the queries must succeed or fail
+// together.
+// Batch does not retry failed queries.
+// Batch requires EdgeDB server version 5.0 or greater.
+func (p *Client) Batch(ctx context.Context, b *Batch) error {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = runBatch(
+		ctx,
+		conn,
+		b,
+		copyState(p.state),
+		p.warningHandler,
+		p.tracer,
+	)
+	return firstError(err, p.release(conn, err))
+}
+
+// Batch runs all of the queries in b in a single round trip.
+// If a query fails the queries after it are skipped.
+func (t *Tx) Batch(ctx context.Context, b *Batch) error {
+	return runBatch(ctx, t, b, t.state, t.warningHandler, t.tracer)
+}
@@ -0,0 +1,143 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	var (
+		many     []int64
+		single   string
+		optional types.OptionalInt64
+	)
+
+	var b Batch
+	b.Execute("SELECT 1")
+	b.Query("SELECT {1, 2, 3}", &many)
+	b.QuerySingle("SELECT <str>$0", &single, "hello")
+	b.QuerySingle("SELECT <int64>{}", &optional)
+	require.Equal(t, 4, b.Len())
+
+	ctx := context.Background()
+	err := client.Batch(ctx, &b)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{1, 2, 3}, many)
+	assert.Equal(t, "hello", single)
+	_, ok := optional.Get()
+	assert.False(t, ok)
+}
+
+func TestBatchEmpty(t *testing.T) {
+	var b Batch
+	err := client.Batch(context.Background(), &b)
+	assert.NoError(t, err)
+}
+
+func TestBatchNoData(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	var result int64
+	var b Batch
+	b.QuerySingle("SELECT <int64>{}", &result)
+
+	err := client.Batch(context.Background(), &b)
+	var edbErr Error
+	require.True(t, errors.As(err, &edbErr), err)
+	assert.True(t, edbErr.Category(NoDataError), err)
+}
+
+func TestBatchSkipsAfterError(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	var (
+		first  int64
+		second int64
+		third  int64
+	)
+
+	var b Batch
+	b.QuerySingle("SELECT 1", &first)
+	b.QuerySingle("SELECT 1 // 0", &second)
+	b.QuerySingle("SELECT 3", &third)
+
+	ctx := context.Background()
+	err := client.Batch(ctx, &b)
+	var edbErr Error
+	require.True(t, errors.As(err, &edbErr), err)
+	assert.True(t, edbErr.Category(DivisionByZeroError), err)
+
+	assert.Equal(t, int64(1), first)
+	assert.Equal(t, int64(0), third)
+
+	// The connection must be usable after a failed batch.
+	err = client.QuerySingle(ctx, "SELECT 42", &third)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), third)
+}
+
+func TestBatchInvalidOut(t *testing.T) {
+	var b Batch
+	b.Query("SELECT 1", nil)
+
+	err := client.Batch(context.Background(), &b)
+	assert.EqualError(t, err, "edgedb.InterfaceError: "+
+		"the \"out\" argument must be a pointer, got untyped nil")
+}
+
+func TestTxBatch(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	err := client.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		var b Batch
+		b.Execute("INSERT TxTest {name := 'Test Batch'}")
+		b.Execute("SELECT 1 / 0")
+		return tx.Batch(ctx, &b)
+	})
+
+	var edbErr Error
+	require.True(t, errors.As(err, &edbErr), err)
+	require.True(t, edbErr.Category(DivisionByZeroError), err)
+
+	var names []string
+	err = client.Query(
+		ctx,
+		"SELECT TxTest.name FILTER TxTest.name = 'Test Batch'",
+		&names,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(names), "The transaction wasn't rolled back")
+}
@@ -19,6 +19,7 @@ package edgedb
 import (
 	"context"
 	"fmt"
+	"io"
 )
 
 type borrowableConn struct {
@@ -40,13 +41,18 @@ func (c *borrowableConn) borrow(reason string) (*protocolConnection, error) {
 			msg: "The transaction is borrowed for a subtransaction. " +
 				"Use the methods on the subtransaction object instead.",
 		}
+	case "iterator":
+		return nil, &interfaceError{
+			msg: "The connection is borrowed by a query iterator. " +
+				"Close the iterator before running other queries.",
+		}
 	default:
 		return nil, &interfaceError{msg: fmt.Sprintf(
 			"existing borrow reason is unexpected: %q", c.reason)}
 	}
 
 	switch reason {
-	case "transaction", "subtransaction":
+	case "transaction", "subtransaction", "iterator":
 		c.reason = reason
 		return c.conn, nil
 	default:
@@ -78,6 +84,11 @@ func (c *borrowableConn) assertUnborrowed() error {
 			msg: "The transaction is borrowed for a subtransaction. " +
 				"Use the methods on the subtransaction object instead.",
 		}
+	case "iterator":
+		return &interfaceError{
+			msg: "The connection is borrowed by a query iterator. " +
+				"Close the iterator before running other queries.",
+		}
 	default:
 		return &interfaceError{msg: fmt.Sprintf(
 			"existing borrow reason is unexpected: %q", c.reason)}
@@ -103,3 +114,27 @@ func (c *borrowableConn) granularFlow(ctx context.Context, q *query) error {
 
 	return c.conn.granularFlow(ctx, q)
 }
+
+func (c *borrowableConn) batchFlow(ctx context.Context, qs []*query) error {
+	if e := c.assertUnborrowed(); e != nil {
+		return e
+	}
+
+	return c.conn.batchFlow(ctx, qs)
+}
+
+func (c *borrowableConn) dumpFlow(ctx context.Context, w io.Writer) error {
+	if e := c.assertUnborrowed(); e != nil {
+		return e
+	}
+
+	return c.conn.dumpFlow(ctx, w)
+}
+
+func (c *borrowableConn) restoreFlow(ctx context.Context, r io.Reader) error {
+	if e := c.assertUnborrowed(); e != nil {
+		return e
+	}
+
+	return c.conn.restoreFlow(ctx, r)
+}
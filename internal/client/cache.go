@@ -109,6 +109,7 @@ func (c *protocolConnection) cacheCapabilities0pX(
 			c.capabilitiesCache.Invalidate()
 		}
 		c.capabilitiesCache.Put(makeKey(q), x)
+		q.reportedCapabilities = x
 	}
 }
 
@@ -123,6 +124,7 @@ func (c *protocolConnection) cacheCapabilities1pX(
 		c.capabilitiesCache.Invalidate()
 	}
 	c.capabilitiesCache.Put(makeKey(q), capabilities)
+	q.reportedCapabilities = capabilities
 }
 
 func (c *reconnectingConn) getCachedCapabilities(q *query) (uint64, bool) {
@@ -19,8 +19,8 @@ package edgedb
 import (
 	"context"
 	"fmt"
-	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/edgedb/edgedb-go/internal/cache"
@@ -55,11 +55,20 @@ type Client struct {
 	txOpts    TxOptions
 	retryOpts RetryOptions
 
+	// idempotent is true if queries with side effects can be retried.
+	idempotent bool
+
 	cfg *connConfig
 	cacheCollection
 	state map[string]interface{}
 
 	warningHandler WarningHandler
+	tracer         Tracer
+
+	// logger is nil if the user did not set Options.Logger.
+	logger Logger
+
+	stats *clientStats
 }
 
 // CreateClient returns a new client. The client connects lazily. Call
@@ -83,9 +92,9 @@ func CreateClientDSN(_ context.Context, dsn string, opts Options) (*Client, erro
 		return nil, err
 	}
 
-	warningHandler := LogWarnings
-	if opts.WarningHandler != nil {
-		warningHandler = opts.WarningHandler
+	warningHandler := opts.WarningHandler
+	if warningHandler == nil {
+		warningHandler = defaultWarningHandler(opts.Logger)
 	}
 
 	False := false
@@ -107,6 +116,9 @@ func CreateClientDSN(_ context.Context, dsn string, opts Options) (*Client, erro
 		},
 		state:          make(map[string]interface{}),
 		warningHandler: warningHandler,
+		tracer:         opts.Tracer,
+		logger:         opts.Logger,
+		stats:          &clientStats{},
 	}
 
 	return p, nil
@@ -119,6 +131,7 @@ func (p *Client) newConn(ctx context.Context) (*transactableConn, error) {
 		reconnectingConn: &reconnectingConn{
 			cfg:             p.cfg,
 			cacheCollection: p.cacheCollection,
+			stats:           p.stats,
 		},
 	}
 
@@ -126,10 +139,44 @@ func (p *Client) newConn(ctx context.Context) (*transactableConn, error) {
 		return nil, err
 	}
 
+	atomic.AddInt64(&p.stats.open, 1)
 	return &conn, nil
 }
 
+// closeConn closes a connection that is being removed from the pool.
+func (p *Client) closeConn(conn *transactableConn) error {
+	atomic.AddInt64(&p.stats.open, -1)
+	return conn.Close()
+}
+
 func (p *Client) acquire(ctx context.Context) (*transactableConn, error) {
+	start := time.Now()
+	conn, err := p.acquireConn(ctx)
+	if p.tracer != nil {
+		p.tracer.TraceAcquire(ctx, AcquireEvent{
+			Start:    start,
+			Duration: time.Since(start),
+			Err:      err,
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	atomic.AddInt64(&p.stats.acquireCount, 1)
+	atomic.AddInt64(&p.stats.acquireDuration, int64(time.Since(start)))
+	atomic.AddInt64(&p.stats.inUse, 1)
+
+	// Connections are shared by copies of the client
+	// which can have different options.
+	conn.txOpts = p.txOpts
+	conn.retryOpts = p.retryOpts
+	conn.idempotent = p.idempotent
+	return conn, nil
+}
+
+func (p *Client) acquireConn(ctx context.Context) (*transactableConn, error) {
 	p.isClosedMutex.RLock()
 	defer p.isClosedMutex.RUnlock()
 
@@ -157,6 +204,7 @@ func (p *Client) acquire(ctx context.Context) (*transactableConn, error) {
 			}
 		}
 
+		atomic.StoreInt64(&p.stats.maxConns, int64(p.concurrency))
 		p.potentialConns = make(chan struct{}, p.concurrency)
 		for i := 0; i < p.concurrency-1; i++ {
 			p.potentialConns <- struct{}{}
@@ -211,9 +259,13 @@ type systemConfig struct {
 }
 
 func (p *Client) release(conn *transactableConn, err error) error {
-	if isClientConnectionError(err) {
+	atomic.AddInt64(&p.stats.inUse, -1)
+	// The socket is closed if the context was canceled
+	// just as the query finished.
+	if isClientConnectionError(err) ||
+		(conn.conn != nil && conn.conn.isClosed()) {
 		p.potentialConns <- struct{}{}
-		return conn.Close()
+		return p.closeConn(conn)
 	}
 
 	timeout := defaultIdleConnectionTimeout
@@ -229,7 +281,7 @@ func (p *Client) release(conn *transactableConn, err error) error {
 		default:
 			// we have MinConns idle so no need to keep this connection.
 			p.potentialConns <- struct{}{}
-			return conn.Close()
+			return p.closeConn(conn)
 		}
 	}
 
@@ -250,15 +302,20 @@ func (p *Client) release(conn *transactableConn, err error) error {
 			case <-time.After(timeout):
 				connChan <- nil
 				p.potentialConns <- struct{}{}
-				if e := conn.Close(); e != nil {
-					log.Println("error while closing idle connection:", e)
+				atomic.AddInt64(&p.stats.idleClosed, 1)
+				if e := p.closeConn(conn); e != nil {
+					loggerOrDefault(p.logger).Error(
+						"error while closing idle connection",
+						"error", e,
+						"conn_id", conn.conn.id,
+					)
 				}
 			}
 		}()
 	default:
 		// we have MinConns idle so no need to keep this connection.
 		p.potentialConns <- struct{}{}
-		return conn.Close()
+		return p.closeConn(conn)
 	}
 
 	return nil
@@ -303,7 +360,7 @@ func (p *Client) Close() error {
 			go func(i int) {
 				conn := acquireIfNotTimedout()
 				if conn != nil {
-					errs[i] = conn.Close()
+					errs[i] = p.closeConn(conn)
 				}
 				wg.Done()
 			}(i)
@@ -335,6 +392,7 @@ func (p *Client) Execute(
 		nil,
 		true,
 		p.warningHandler,
+		p.tracer,
 	)
 	if err != nil {
 		return err
@@ -357,10 +415,57 @@ func (p *Client) Query(
 	}
 
 	err = runQuery(
-		ctx, conn, "Query", cmd, out, args, p.state, p.warningHandler)
+		ctx,
+		conn,
+		"Query",
+		cmd,
+		out,
+		args,
+		p.state,
+		p.warningHandler,
+		p.tracer,
+	)
 	return firstError(err, p.release(conn, err))
 }
 
+// QueryIter runs a query and returns an iterator over its results.
+// The iterator must be closed, either by calling Rows.Close()
+// or by reading until Rows.Next() returns false,
+// to return the connection to the pool.
+// Unlike Query, QueryIter does not retry failed queries.
+// QueryIter requires EdgeDB server version 5.0 or greater.
+func (p *Client) QueryIter(
+	ctx context.Context,
+	cmd string,
+	args ...interface{},
+) (*Rows, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = conn.ensureConnection(ctx); err != nil {
+		return nil, firstError(err, p.release(conn, err))
+	}
+
+	rows, err := runQueryIter(
+		ctx,
+		conn.conn,
+		cmd,
+		args,
+		conn.capabilities1pX(),
+		p.state,
+		p.warningHandler,
+		p.tracer,
+		func(err error) error { return p.release(conn, err) },
+	)
+	if err != nil {
+		return nil, firstError(err, p.release(conn, err))
+	}
+
+	return rows, nil
+}
+
 // QuerySingle runs a singleton-returning query and returns its element.
 // If the query executes successfully but doesn't return a result
 // a NoDataError is returned. If the out argument is an optional type the out
@@ -385,6 +490,7 @@ func (p *Client) QuerySingle(
 		args,
 		p.state,
 		p.warningHandler,
+		p.tracer,
 	)
 	return firstError(err, p.release(conn, err))
 }
@@ -410,6 +516,7 @@ func (p *Client) QueryJSON(
 		args,
 		p.state,
 		p.warningHandler,
+		p.tracer,
 	)
 	return firstError(err, p.release(conn, err))
 }
@@ -437,6 +544,7 @@ func (p *Client) QuerySingleJSON(
 		args,
 		p.state,
 		p.warningHandler,
+		p.tracer,
 	)
 	return firstError(err, p.release(conn, err))
 }
@@ -448,6 +556,7 @@ func (p *Client) QuerySingleJSON(
 // The default rule can be set with WithRetryRule().
 // For more fine grained control a retry rule can be set
 // for each defined RetryCondition using WithRetryCondition().
+// Other errors can be retried by adding a RetryPredicate.
 // When a transaction fails but is retryable
 // the rule for the failure condition is used to determine if the transaction
 // should be tried again based on RetryRule.Attempts and the amount of time
@@ -455,12 +564,56 @@ func (p *Client) QuerySingleJSON(
 // If either field is unset (see RetryRule) then the default rule is used.
 // If the object's default is unset the fall back is 3 attempts
 // and exponential backoff.
+// Waiting between attempts stops as soon as ctx is done. No attempt is
+// started after ctx's deadline or after RetryRule.WithMaxElapsed() has passed.
 func (p *Client) Tx(ctx context.Context, action TxBlock) error {
 	conn, err := p.acquire(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = conn.tx(ctx, action, p.state, p.warningHandler)
+	err = conn.tx(ctx, action, p.state, p.warningHandler, p.tracer)
 	return firstError(err, p.release(conn, err))
 }
+
+// BeginTx starts a transaction and returns it. The transaction holds a
+// connection from the pool until it is finished with Tx.Commit() or
+// Tx.Rollback(). Unlike Tx, failed transactions are not retried.
+// Use IsRetryable() to decide if a failed transaction should be run again.
+func (p *Client) BeginTx(ctx context.Context, opts TxOptions) (*Tx, error) {
+	if !opts.fromFactory {
+		panic("TxOptions not created with NewTxOptions() are not valid")
+	}
+
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = conn.ensureConnection(ctx); err != nil {
+		return nil, firstError(err, p.release(conn, err))
+	}
+
+	borrowed, err := conn.borrow("transaction")
+	if err != nil {
+		return nil, firstError(err, p.release(conn, err))
+	}
+
+	tx := &Tx{
+		borrowableConn: borrowableConn{conn: borrowed},
+		txState:        &txState{},
+		options:        opts,
+		state:          p.state,
+		warningHandler: p.warningHandler,
+		tracer:         p.tracer,
+		release: func(err error) error {
+			return firstError(conn.unborrow(), p.release(conn, err))
+		},
+	}
+
+	if err = tx.start(ctx); err != nil {
+		return nil, firstError(err, tx.release(err))
+	}
+
+	return tx, nil
+}
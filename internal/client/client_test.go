@@ -21,6 +21,7 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/edgedb/edgedb-go/internal/edgedbtypes"
 	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
@@ -136,6 +137,43 @@ func TestClientTx(t *testing.T) {
 	require.Equal(t, int64(693), result, "Client.Tx() failed")
 }
 
+func TestClientStats(t *testing.T) {
+	ctx := context.Background()
+
+	p, err := CreateClient(ctx, opts)
+	require.NoError(t, err)
+	defer p.Close() // nolint:errcheck
+
+	stats := p.Stats()
+	assert.Equal(t, ClientStats{}, stats)
+
+	var result int64
+	err = p.QuerySingle(ctx, "SELECT 1", &result)
+	require.NoError(t, err)
+
+	err = p.WithTxOptions(NewTxOptions()).Tx(
+		ctx,
+		func(ctx context.Context, tx *Tx) error {
+			stats := p.Stats()
+			assert.Equal(t, 1, stats.InUse)
+			assert.Equal(t, 0, stats.Idle)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	stats = p.Stats()
+	assert.Equal(t, p.concurrency, stats.MaxConnections)
+	assert.Equal(t, 1, stats.OpenConnections)
+	assert.Equal(t, 0, stats.InUse)
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, int64(2), stats.AcquireCount)
+	assert.Greater(t, int64(stats.AcquireDuration), int64(0))
+	assert.Equal(t, int64(0), stats.IdleClosed)
+	assert.Equal(t, int64(0), stats.Reconnects)
+	assert.Equal(t, int64(0), stats.Retries)
+}
+
 func TestQuerySingleMissingResult(t *testing.T) {
 	ctx := context.Background()
 
@@ -256,3 +294,89 @@ func TestConcurentClientUsage(t *testing.T) {
 
 	done.Wait()
 }
+
+func TestClientWithIdempotent(t *testing.T) {
+	ctx := context.Background()
+
+	isDivisionByZero := func(err Error) bool {
+		return err.Category(DivisionByZeroError)
+	}
+	noWait := func(int) time.Duration { return 0 }
+
+	retries := 0
+	retryOpts := NewRetryOptions().
+		WithPredicate(isDivisionByZero, NewRetryRule().WithBackoff(noWait)).
+		OnRetry(func(int, error, time.Duration) { retries++ })
+
+	query := `
+		SELECT (INSERT TxTest { name := 'Idempotent' }).name
+			++ <str>(1 // <int64>$0)`
+
+	// Queries with side effects are not retried by default.
+	var result string
+	err := client.WithRetryOptions(retryOpts).
+		QuerySingle(ctx, query, &result, int64(0))
+	assert.Error(t, err)
+	assert.Equal(t, 0, retries)
+
+	idempotent := client.WithRetryOptions(retryOpts).WithIdempotent()
+	err = idempotent.QuerySingle(ctx, query, &result, int64(0))
+	assert.Error(t, err)
+	assert.Equal(t, 2, retries)
+
+	retries = 0
+	err = idempotent.Execute(ctx, query, int64(0))
+	assert.Error(t, err)
+	assert.Equal(t, 2, retries)
+
+	// The original client is not changed.
+	assert.False(t, client.idempotent)
+}
+
+func TestIdempotentMutationRetriedOnConnectionError(t *testing.T) {
+	ctx := context.Background()
+
+	// run executes a mutation and closes the socket
+	// right before the first attempt is sent to the server
+	// so that it fails with a ClientConnectionClosedError.
+	run := func(idempotent bool) (int, error) {
+		conn, err := client.acquire(ctx)
+		require.NoError(t, err)
+		conn.idempotent = idempotent
+
+		q, err := newQuery(
+			"Execute",
+			"INSERT TxTest { name := 'IdempotentReconnect' }",
+			nil,
+			conn.capabilities1pX(),
+			copyState(client.state),
+			nil,
+			true,
+			nil,
+			nil,
+		)
+		require.NoError(t, err)
+
+		attempts := 0
+		err = conn.retryFlow(ctx, q,
+			func(ctx context.Context, q *query) error {
+				attempts++
+				if attempts == 1 {
+					require.NoError(t, conn.conn.soc.Close())
+				}
+				return conn.borrowableConn.granularFlow(ctx, q)
+			})
+		return attempts, firstError(err, client.release(conn, err))
+	}
+
+	attempts, err := run(true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	// Mutations are not retried unless the client is idempotent.
+	attempts, err = run(false)
+	var edbErr Error
+	require.True(t, errors.As(err, &edbErr))
+	assert.True(t, edbErr.Category(ClientConnectionClosedError))
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,55 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"reflect"
+
+	"github.com/edgedb/edgedb-go/internal/codecs"
+)
+
+// CodecEncodeFunc encodes value into the binary wire format
+// of the scalar type it was registered for.
+type CodecEncodeFunc func(value interface{}) ([]byte, error)
+
+// CodecDecodeFunc decodes data from the binary wire format of the scalar
+// type it was registered for. The returned value must be assignable to the
+// go type it was registered for.
+type CodecDecodeFunc func(data []byte) (interface{}, error)
+
+// RegisterCodec makes encode and decode the codec for goType values
+// of the scalar type named typeName, for example std::str, cal::local_date,
+// ext::pgvector::vector or default::my_scalar. Registered codecs are used
+// before the built in codecs. A codec registered for a scalar type is also
+// used for scalar types that extend it unless they have their own codec.
+//
+// Types that are not built in are matched by name only with EdgeDB 5.0
+// or newer. If goType's pointer implements SetMissing(bool) it can be used
+// for optional shape fields, and if goType implements Missing() bool
+// it can be used for optional arguments.
+//
+// RegisterCodec should be called before any queries are run, usually in
+// an init function. It panics if typeName is empty, if goType, encode or
+// decode is nil, or if it is called twice for the same typeName and goType.
+func RegisterCodec(
+	typeName string,
+	goType reflect.Type,
+	encode CodecEncodeFunc,
+	decode CodecDecodeFunc,
+) {
+	codecs.RegisterCodec(typeName, goType, encode, decode)
+}
@@ -0,0 +1,62 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	RegisterCodec(
+		"std::str",
+		reflect.TypeOf(netip.Addr{}),
+		func(val interface{}) ([]byte, error) {
+			return []byte(val.(netip.Addr).String()), nil
+		},
+		func(data []byte) (interface{}, error) {
+			return netip.ParseAddr(string(data))
+		},
+	)
+}
+
+func TestRegisteredCodec(t *testing.T) {
+	ctx := context.Background()
+	addr := netip.MustParseAddr("192.168.0.1")
+
+	var result netip.Addr
+	err := client.QuerySingle(ctx, "SELECT <str>$0", &result, addr)
+	require.NoError(t, err)
+	assert.Equal(t, addr, result)
+
+	var str string
+	err = client.QuerySingle(ctx, "SELECT <str>$0", &str, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", str)
+
+	var shape struct {
+		Addr netip.Addr `edgedb:"addr"`
+	}
+	err = client.QuerySingle(ctx, "SELECT { addr := '::1' }", &shape)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("::1"), shape.Addr)
+}
@@ -25,7 +25,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/url"
 	"os"
 	"path"
@@ -69,6 +68,7 @@ type connConfig struct {
 	tlsServerName      string
 	serverSettings     *snc.ServerSettings
 	secretKey          string
+	logger             Logger
 }
 
 func (c *connConfig) tlsConfig() (*tls.Config, error) {
@@ -141,6 +141,7 @@ type configResolver struct {
 	profile            cfgVal // string
 	instance           cfgVal // string
 	org                cfgVal // string
+	logger             Logger
 }
 
 func (r *configResolver) setInstance(val, source string) error {
@@ -835,8 +836,8 @@ func (r *configResolver) resolveEnvVars(paths *cfgPaths) (bool, error) {
 	port, portOk := os.LookupEnv("EDGEDB_PORT")
 	if portOk && strings.HasPrefix(port, "tcp://") {
 		// EDGEDB_PORT is set by 'docker --link' so ignore and warn
-		log.Println(
-			"Warning: ignoring EDGEDB_PORT in 'tcp://host:port' format")
+		loggerOrDefault(r.logger).Warn(
+			"ignoring EDGEDB_PORT in 'tcp://host:port' format")
 		portOk = false
 	}
 
@@ -1044,6 +1045,7 @@ func (r *configResolver) config(opts *Options) (*connConfig, error) {
 		tlsSecurity:        tlsSecurity,
 		tlsServerName:      tlsServerName,
 		secretKey:          secretKey,
+		logger:             opts.Logger,
 	}, nil
 }
 
@@ -1084,7 +1086,10 @@ func newConfigResolver(
 	opts *Options,
 	paths *cfgPaths,
 ) (*configResolver, error) {
-	cfg := &configResolver{serverSettings: snc.NewServerSettings()}
+	cfg := &configResolver{
+		serverSettings: snc.NewServerSettings(),
+		logger:         opts.Logger,
+	}
 
 	var instance string
 	if !isDSNLike.MatchString(dsn) {
@@ -0,0 +1,364 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1" // nolint:gosec
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+)
+
+// Dump files start with dumpMagic followed by the dump format version as a
+// big endian int64. The rest of the file is a sequence of packets.
+// Each packet is made of a packet type byte, the sha1 hash of the packet
+// data, the data length as a big endian uint32 and the data itself.
+// The first packet is the dump header. All other packets are data blocks.
+const (
+	dumpMagic         = "\xff\xd8\x00\x00\xd8EDGEDB\x00DUMP\x00"
+	dumpFormatVersion = 1
+
+	dumpPacketHeader = 'H'
+	dumpPacketBlock  = 'D'
+)
+
+// Dump writes a dump of the client's database to w.
+// The dump uses the same file format as the edgedb dump command
+// and can be restored with Restore() or the edgedb restore command.
+func (p *Client) Dump(ctx context.Context, w io.Writer) error {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = conn.dumpFlow(ctx, w)
+	return firstError(err, p.release(conn, err))
+}
+
+// Restore restores a dump read from r into the client's database.
+// The database must be empty.
+func (p *Client) Restore(ctx context.Context, r io.Reader) error {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = conn.restoreFlow(ctx, r)
+	return firstError(err, p.release(conn, err))
+}
+
+func writeDumpPacket(w io.Writer, typ byte, data []byte) error {
+	var prefix [25]byte
+	prefix[0] = typ
+	hash := sha1.Sum(data) // nolint:gosec
+	copy(prefix[1:21], hash[:])
+	binary.BigEndian.PutUint32(prefix[21:], uint32(len(data)))
+
+	if _, err := w.Write(prefix[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(data)
+	return err
+}
+
+// readDumpPacket reads the next packet from r.
+// It returns io.EOF if there are no more packets.
+func readDumpPacket(r io.Reader) (byte, []byte, error) {
+	var prefix [25]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return 0, nil, errMalformedDump("truncated packet")
+		}
+		return 0, nil, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint32(prefix[21:]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return 0, nil, errMalformedDump("truncated packet")
+		}
+		return 0, nil, err
+	}
+
+	hash := sha1.Sum(data) // nolint:gosec
+	if !bytes.Equal(hash[:], prefix[1:21]) {
+		return 0, nil, errMalformedDump("checksum mismatch")
+	}
+
+	return prefix[0], data, nil
+}
+
+func readDumpPreamble(r io.Reader) error {
+	var preamble [len(dumpMagic) + 8]byte
+	if _, err := io.ReadFull(r, preamble[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errMalformedDump("not a dump file")
+		}
+		return err
+	}
+
+	if string(preamble[:len(dumpMagic)]) != dumpMagic {
+		return errMalformedDump("not a dump file")
+	}
+
+	version := int64(binary.BigEndian.Uint64(preamble[len(dumpMagic):]))
+	if version < 1 || version > dumpFormatVersion {
+		return errMalformedDump(fmt.Sprintf(
+			"unsupported dump format version %v", version))
+	}
+
+	return nil
+}
+
+func errMalformedDump(msg string) error {
+	return &invalidArgumentError{msg: "invalid dump file: " + msg}
+}
+
+func (c *protocolConnection) dumpFlow(
+	ctx context.Context,
+	out io.Writer,
+) error {
+	r, err := c.acquireReader(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline, _ := ctx.Deadline()
+	err = c.soc.SetDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	stop := c.watchCancel(ctx)
+	err = stop(c.execDumpFlow(r, out))
+	return firstError(err, c.releaseReader(r))
+}
+
+func (c *protocolConnection) execDumpFlow(
+	r *buff.Reader,
+	out io.Writer,
+) error {
+	w := buff.NewWriter(c.writeMemory[:0])
+	w.BeginMessage(uint8(Dump))
+	w.PushUint16(0) // no headers
+	w.EndMessage()
+
+	w.BeginMessage(uint8(Sync))
+	w.EndMessage()
+
+	if e := c.soc.WriteAll(w.Unwrap()); e != nil {
+		return &clientConnectionClosedError{err: e}
+	}
+
+	var err error
+	// writeErr is the first error returned by out. All messages must be
+	// read even if out fails so that the connection remains usable.
+	var writeErr error
+	write := func(typ byte) {
+		if writeErr == nil {
+			writeErr = writeDumpPacket(out, typ, r.Buf)
+		}
+		r.DiscardMessage()
+	}
+
+	if _, e := io.WriteString(out, dumpMagic); e != nil {
+		writeErr = e
+	} else {
+		var version [8]byte
+		binary.BigEndian.PutUint64(version[:], dumpFormatVersion)
+		_, writeErr = out.Write(version[:])
+	}
+
+	done := buff.NewSignal()
+
+	for r.Next(done.Chan) {
+		switch Message(r.MsgType) {
+		case DumpHeader:
+			write(dumpPacketHeader)
+		case DumpBlock:
+			write(dumpPacketBlock)
+		case CommandComplete:
+			r.DiscardMessage()
+		case ReadyForCommand:
+			decodeReadyForCommandMsg(r)
+			done.Signal()
+		case ErrorResponse:
+			err = wrapAll(err, decodeErrorResponseMsg(r, ""))
+		default:
+			if e := c.fallThrough(r); e != nil {
+				// the connection will not be usable after this x_x
+				return e
+			}
+		}
+	}
+
+	if writeErr != nil {
+		err = wrapAll(err, &interfaceError{
+			err: fmt.Errorf("writing dump: %w", writeErr),
+		})
+	}
+
+	return wrapAll(err, r.Err)
+}
+
+func (c *protocolConnection) restoreFlow(
+	ctx context.Context,
+	in io.Reader,
+) error {
+	if e := readDumpPreamble(in); e != nil {
+		return wrapReadDumpError(e)
+	}
+
+	typ, header, err := readDumpPacket(in)
+	if err == io.EOF || (err == nil && typ != dumpPacketHeader) {
+		return errMalformedDump("missing dump header")
+	}
+	if err != nil {
+		return wrapReadDumpError(err)
+	}
+
+	r, err := c.acquireReader(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline, _ := ctx.Deadline()
+	err = c.soc.SetDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	stop := c.watchCancel(ctx)
+	err = stop(c.execRestoreFlow(r, header, in))
+	return firstError(err, c.releaseReader(r))
+}
+
+func wrapReadDumpError(err error) error {
+	if _, ok := err.(*invalidArgumentError); ok {
+		return err
+	}
+
+	return &interfaceError{err: fmt.Errorf("reading dump: %w", err)}
+}
+
+func (c *protocolConnection) execRestoreFlow(
+	r *buff.Reader,
+	header []byte,
+	in io.Reader,
+) error {
+	w := buff.NewWriter(c.writeMemory[:0])
+	w.BeginMessage(uint8(Restore))
+	w.PushUint16(0) // no headers
+	w.PushUint16(1) // jobs
+	w.PushBytes(header)
+	w.EndMessage()
+
+	if e := c.soc.WriteAll(w.Unwrap()); e != nil {
+		return &clientConnectionClosedError{err: e}
+	}
+
+	// The server sends RestoreReady if it accepts the header.
+	// Otherwise it sends ErrorResponse followed by ReadyForCommand.
+	var err error
+	ready := false
+	done := buff.NewSignal()
+
+	for r.Next(done.Chan) {
+		switch Message(r.MsgType) {
+		case RestoreReady:
+			r.DiscardMessage()
+			ready = true
+			done.Signal()
+		case ReadyForCommand:
+			decodeReadyForCommandMsg(r)
+			done.Signal()
+		case ErrorResponse:
+			err = wrapAll(err, decodeErrorResponseMsg(r, ""))
+		default:
+			if e := c.fallThrough(r); e != nil {
+				// the connection will not be usable after this x_x
+				return e
+			}
+		}
+	}
+
+	if r.Err != nil {
+		return wrapAll(err, r.Err)
+	}
+
+	if !ready {
+		return err
+	}
+
+	// A failure to read the dump can not be reported to the server. Closing
+	// the connection is the only way to abort the restore.
+	for {
+		typ, data, e := readDumpPacket(in)
+		if e == io.EOF {
+			break
+		}
+		if e == nil && typ != dumpPacketBlock {
+			e = errMalformedDump(fmt.Sprintf("unexpected packet %q", typ))
+		}
+		if e != nil {
+			return wrapAll(wrapReadDumpError(e), c.soc.Close())
+		}
+
+		w = buff.NewWriter(c.writeMemory[:0])
+		w.BeginMessage(uint8(RestoreBlock))
+		w.PushBytes(data)
+		w.EndMessage()
+
+		if e := c.soc.WriteAll(w.Unwrap()); e != nil {
+			return &clientConnectionClosedError{err: e}
+		}
+	}
+
+	w = buff.NewWriter(c.writeMemory[:0])
+	w.BeginMessage(uint8(RestoreEOF))
+	w.EndMessage()
+
+	if e := c.soc.WriteAll(w.Unwrap()); e != nil {
+		return &clientConnectionClosedError{err: e}
+	}
+
+	done = buff.NewSignal()
+
+	for r.Next(done.Chan) {
+		switch Message(r.MsgType) {
+		case CommandComplete:
+			r.DiscardMessage()
+		case ReadyForCommand:
+			decodeReadyForCommandMsg(r)
+			done.Signal()
+		case ErrorResponse:
+			err = wrapAll(err, decodeErrorResponseMsg(r, ""))
+		default:
+			if e := c.fallThrough(r); e != nil {
+				// the connection will not be usable after this x_x
+				return e
+			}
+		}
+	}
+
+	return wrapAll(err, r.Err)
+}
@@ -0,0 +1,172 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpPacketRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeDumpPacket(&buf, dumpPacketBlock, []byte("abc")))
+
+	typ, data, err := readDumpPacket(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, byte(dumpPacketBlock), typ)
+	assert.Equal(t, []byte("abc"), data)
+
+	_, _, err = readDumpPacket(&buf)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestDumpPacketChecksumMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeDumpPacket(&buf, dumpPacketBlock, []byte("abc")))
+	b := buf.Bytes()
+	b[len(b)-1] = 'x'
+
+	_, _, err := readDumpPacket(&buf)
+	assert.EqualError(t, err,
+		"edgedb.InvalidArgumentError: invalid dump file: checksum mismatch")
+}
+
+func TestDumpPreamble(t *testing.T) {
+	err := readDumpPreamble(strings.NewReader(dumpMagic +
+		"\x00\x00\x00\x00\x00\x00\x00\x01"))
+	assert.NoError(t, err)
+
+	err = readDumpPreamble(strings.NewReader(dumpMagic +
+		"\x00\x00\x00\x00\x00\x00\x00\x02"))
+	assert.EqualError(t, err, "edgedb.InvalidArgumentError: "+
+		"invalid dump file: unsupported dump format version 2")
+
+	err = readDumpPreamble(strings.NewReader("not a dump"))
+	assert.EqualError(t, err,
+		"edgedb.InvalidArgumentError: invalid dump file: not a dump file")
+}
+
+func TestDump(t *testing.T) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	require.NoError(t, client.Dump(ctx, &buf))
+	require.NoError(t, readDumpPreamble(&buf))
+
+	typ, _, err := readDumpPacket(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, byte(dumpPacketHeader), typ)
+
+	for {
+		typ, _, err = readDumpPacket(&buf)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, byte(dumpPacketBlock), typ)
+	}
+
+	// The connection is still usable after a dump.
+	var result int64
+	require.NoError(t, client.QuerySingle(ctx, "SELECT 1", &result))
+	assert.Equal(t, int64(1), result)
+}
+
+func TestRestoreInvalidDump(t *testing.T) {
+	ctx := context.Background()
+	err := client.Restore(ctx, strings.NewReader("not a dump"))
+	assert.EqualError(t, err,
+		"edgedb.InvalidArgumentError: invalid dump file: not a dump file")
+}
+
+func TestRestoreNonEmptyDatabase(t *testing.T) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	require.NoError(t, client.Dump(ctx, &buf))
+
+	// The test database has a schema so the server rejects the restore.
+	err := client.Restore(ctx, &buf)
+	require.Error(t, err)
+
+	var edbErr Error
+	require.True(t, errors.As(err, &edbErr))
+
+	// The connection is still usable after a rejected restore.
+	var result int64
+	require.NoError(t, client.QuerySingle(ctx, "SELECT 1", &result))
+	assert.Equal(t, int64(1), result)
+}
+
+// createTestBranch creates an empty branch that is dropped
+// when the test is done and returns a client connected to it.
+func createTestBranch(t *testing.T, prefix string) *Client {
+	ctx := context.Background()
+	name := fmt.Sprintf("%v_%v", prefix, time.Now().UnixNano())
+	require.NoError(t, client.Execute(ctx,
+		fmt.Sprintf("CREATE EMPTY BRANCH %v", name)))
+
+	o := opts
+	o.Branch = name
+	c, err := CreateClient(ctx, o)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, c.Close())
+		assert.NoError(t, client.Execute(ctx,
+			fmt.Sprintf("DROP BRANCH %v", name)))
+	})
+
+	return c
+}
+
+func TestDumpRestoreRoundTrip(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	source := createTestBranch(t, "dump_source")
+	require.NoError(t, source.Execute(ctx, `
+		CREATE TYPE DumpTest {
+			CREATE REQUIRED PROPERTY name -> str;
+		};`))
+	require.NoError(t, source.Execute(ctx, `
+		FOR name IN {'a', 'b', 'c'}
+		UNION (INSERT DumpTest { name := name });`))
+
+	var buf bytes.Buffer
+	require.NoError(t, source.Dump(ctx, &buf))
+
+	target := createTestBranch(t, "restore_target")
+	require.NoError(t, target.Restore(ctx, &buf))
+
+	var names []string
+	err := target.Query(ctx,
+		"SELECT DumpTest.name ORDER BY DumpTest.name", &names)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+}
@@ -18,7 +18,8 @@ package edgedb
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/edgedb/edgedb-go/internal"
@@ -37,7 +38,13 @@ type cacheCollection struct {
 	capabilitiesCache *cache.Cache // nolint:structcheck
 }
 
+// connIDCounter is used to give each protocolConnection a unique id
+// for logging.
+var connIDCounter uint64
+
 type protocolConnection struct {
+	id                  uint64
+	logger              Logger
 	soc                 *autoClosingSocket
 	writeMemory         [1024]byte
 	acquireReaderSignal chan struct{}
@@ -68,6 +75,8 @@ func connectWithTimeout(
 	}
 
 	conn := &protocolConnection{
+		id:                  atomic.AddUint64(&connIDCounter, 1),
+		logger:              loggerOrDefault(cfg.logger),
 		soc:                 socket,
 		acquireReaderSignal: make(chan struct{}, 1),
 		readerChan:          make(chan *buff.Reader, 1),
@@ -109,7 +118,9 @@ func (c *protocolConnection) acquireReader(
 		}
 		return r, nil
 	case <-ctx.Done():
-		return nil, wrapNetError(ctx.Err())
+		// Nothing has been written to the socket
+		// so the connection is still usable.
+		return nil, fmt.Errorf("edgedb: %w", ctx.Err())
 	}
 }
 
@@ -127,15 +138,27 @@ func (c *protocolConnection) releaseReader(r *buff.Reader) error {
 			switch Message(r.MsgType) {
 			case ErrorResponse:
 				err := decodeErrorResponseMsg(r, "")
-				log.Println("from background:", err)
+				c.logger.Error(
+					"error received in background",
+					"error", err,
+					"conn_id", c.id,
+				)
 				r.Err = wrapAll(r.Err, err)
 			default:
 				if e := c.fallThrough(r); e != nil {
-					log.Println("fall through in background:", e)
+					c.logger.Error(
+						"unexpected message received in background",
+						"error", e,
+						"conn_id", c.id,
+					)
 					r.Err = wrapAll(r.Err, e)
 					err := c.soc.Close()
 					if err != nil {
-						log.Println("error closing socket:", err)
+						c.logger.Error(
+							"error closing socket",
+							"error", err,
+							"conn_id", c.id,
+						)
 					}
 					c.readerChan <- r
 					return
@@ -146,7 +169,11 @@ func (c *protocolConnection) releaseReader(r *buff.Reader) error {
 		if r.Err != nil {
 			err := c.soc.Close()
 			if err != nil {
-				log.Println("error closing socket:", err)
+				c.logger.Error(
+					"error closing socket",
+					"error", err,
+					"conn_id", c.id,
+				)
 			}
 		}
 		c.readerChan <- r
@@ -155,6 +182,56 @@ func (c *protocolConnection) releaseReader(r *buff.Reader) error {
 	return nil
 }
 
+// watchCancel closes the socket if ctx is done before the returned function
+// is called. Closing the socket is the only way to interrupt a running
+// query. The returned function must be called once the query is finished.
+// It returns a queryCanceledError if the query failed because ctx is done,
+// otherwise it returns err. A query that succeeded is not reported as
+// canceled even if the socket was closed after it finished, the closed
+// connection is discarded when it is released.
+func (c *protocolConnection) watchCancel(
+	ctx context.Context,
+) func(err error) error {
+	if ctx.Done() == nil {
+		return func(err error) error { return err }
+	}
+
+	stop := make(chan struct{})
+	canceled := make(chan bool, 1)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			if err := c.soc.Close(); err != nil {
+				c.logger.Error(
+					"error closing socket",
+					"error", err,
+					"conn_id", c.id,
+				)
+			}
+			canceled <- true
+		case <-stop:
+			canceled <- false
+		}
+	}()
+
+	return func(err error) error {
+		close(stop)
+		wasCanceled := <-canceled
+		if err == nil {
+			return nil
+		}
+
+		// The socket may have timed out on ctx's deadline
+		// before ctx.Done() was closed.
+		if wasCanceled || ctx.Err() != nil {
+			return &queryCanceledError{err: ctx.Err()}
+		}
+
+		return err
+	}
+}
+
 // Close the db connection
 func (c *protocolConnection) close() error {
 	if c.soc == nil {
@@ -182,7 +259,14 @@ func (c *protocolConnection) isClosed() bool {
 	return false
 }
 
-func (c *protocolConnection) scriptFlow(ctx context.Context, q *query) error {
+func (c *protocolConnection) scriptFlow(
+	ctx context.Context,
+	q *query,
+) (err error) {
+	q.rows = 0
+	start := time.Now()
+	defer func() { c.traceQuery(ctx, q, start, err) }()
+
 	r, err := c.acquireReader(ctx)
 	if err != nil {
 		return err
@@ -194,6 +278,7 @@ func (c *protocolConnection) scriptFlow(ctx context.Context, q *query) error {
 		return err
 	}
 
+	stop := c.watchCancel(ctx)
 	switch {
 	case c.protocolVersion.GTE(protocolVersion2p0):
 		err = c.execGranularFlow2pX(r, q)
@@ -203,13 +288,18 @@ func (c *protocolConnection) scriptFlow(ctx context.Context, q *query) error {
 		err = c.execScriptFlow(r, q)
 	}
 
+	err = stop(err)
 	return firstError(err, c.releaseReader(r))
 }
 
 func (c *protocolConnection) granularFlow(
 	ctx context.Context,
 	q *query,
-) error {
+) (err error) {
+	q.rows = 0
+	start := time.Now()
+	defer func() { c.traceQuery(ctx, q, start, err) }()
+
 	r, err := c.acquireReader(ctx)
 	if err != nil {
 		return err
@@ -221,6 +311,7 @@ func (c *protocolConnection) granularFlow(
 		return err
 	}
 
+	stop := c.watchCancel(ctx)
 	switch {
 	case c.protocolVersion.GTE(protocolVersion2p0):
 		err = c.execGranularFlow2pX(r, q)
@@ -230,5 +321,34 @@ func (c *protocolConnection) granularFlow(
 		err = c.execGranularFlow0pX(r, q)
 	}
 
+	err = stop(err)
+	return firstError(err, c.releaseReader(r))
+}
+
+func (c *protocolConnection) batchFlow(
+	ctx context.Context,
+	qs []*query,
+) (err error) {
+	if !c.protocolVersion.GTE(protocolVersion2p0) {
+		return errBatchNotSupported
+	}
+
+	start := time.Now()
+	traces := make([]batchTrace, len(qs))
+	defer func() { c.traceBatch(ctx, qs, traces, start, err) }()
+
+	r, err := c.acquireReader(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline, _ := ctx.Deadline()
+	err = c.soc.SetDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	stop := c.watchCancel(ctx)
+	err = stop(c.execBatchFlow2pX(r, qs, traces, start))
 	return firstError(err, c.releaseReader(r))
 }
@@ -23,6 +23,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/edgedb/edgedb-go/internal/buff"
@@ -36,6 +37,14 @@ var (
 		"are not supported by the server. " +
 		"Upgrade your server to version 2.0 or greater " +
 		"to use these features."}
+	errIterNotSupported = &interfaceError{msg: "QueryIter " +
+		"is not supported by the server. " +
+		"Upgrade your server to version 5.0 or greater " +
+		"to use this feature."}
+	errBatchNotSupported = &interfaceError{msg: "Batch " +
+		"is not supported by the server. " +
+		"Upgrade your server to version 5.0 or greater " +
+		"to use this feature."}
 )
 
 // ErrorTag is the argument type to Error.HasTag().
@@ -123,6 +132,35 @@ func decodeErrorResponseMsg(r *buff.Reader, query string) error {
 	return w.Err(query)
 }
 
+// queryCanceledError is returned when a query is interrupted because its
+// context is done. The query's connection is closed so that it is not
+// reused, but unlike other connection errors the query is not retried.
+type queryCanceledError struct {
+	err error
+}
+
+func (e *queryCanceledError) Error() string {
+	return "edgedb.ClientConnectionClosedError: query canceled: " +
+		e.err.Error()
+}
+
+func (e *queryCanceledError) Unwrap() error { return e.err }
+
+func (e *queryCanceledError) Category(c ErrorCategory) bool {
+	switch c {
+	case ClientConnectionClosedError:
+		return true
+	case ClientConnectionError:
+		return true
+	case ClientError:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *queryCanceledError) HasTag(_ ErrorTag) bool { return false }
+
 type wrappedManyError struct {
 	msg  string
 	errs []error
@@ -176,6 +214,26 @@ func wrapAll(errs ...error) error {
 	return err
 }
 
+// IsRetryable returns true if err indicates that a failed transaction
+// might succeed if it is run again. Client.Tx() retries these errors
+// automatically, transactions started with Client.BeginTx() are not retried.
+func IsRetryable(err error) bool {
+	var edbErr Error
+	return errors.As(err, &edbErr) && edbErr.HasTag(ShouldRetry)
+}
+
+// isRepeatableReadUnsupported returns true if err is the server's error for
+// a statement that can not be run in a repeatable read transaction.
+func isRepeatableReadUnsupported(err error) bool {
+	var edbErr Error
+	return errors.As(err, &edbErr) &&
+		!edbErr.Category(ClientError) &&
+		strings.Contains(
+			strings.ToLower(edbErr.Error()),
+			"not supported in repeatable read",
+		)
+}
+
 func isClientConnectionError(err error) bool {
 	var edbErr Error
 	return errors.As(err, &edbErr) && edbErr.Category(ClientConnectionError)
@@ -18,7 +18,6 @@ package edgedb
 
 import (
 	"fmt"
-	"log"
 	"reflect"
 	"strconv"
 	"unsafe"
@@ -35,6 +34,28 @@ var logMsgSeverityLookup = map[uint8]string{
 	0x50: "WARNING",
 }
 
+func (c *protocolConnection) logServerMessage(
+	severity uint8,
+	code uint32,
+	message string,
+) {
+	args := []interface{}{
+		"severity", logMsgSeverityLookup[severity],
+		"code", code,
+		"message", message,
+		"conn_id", c.id,
+	}
+
+	switch logMsgSeverityLookup[severity] {
+	case "DEBUG":
+		c.logger.Debug("server message", args...)
+	case "WARNING":
+		c.logger.Warn("server message", args...)
+	default:
+		c.logger.Info("server message", args...)
+	}
+}
+
 func (c *protocolConnection) fallThrough(r *buff.Reader) error {
 	if c.protocolVersion.GTE(protocolVersion2p0) {
 		return c.fallThrough2pX(r)
@@ -92,11 +113,11 @@ func (c *protocolConnection) fallThrough(r *buff.Reader) error {
 				"got ParameterStatus for unknown parameter %q", name)}
 		}
 	case LogMessage:
-		severity := logMsgSeverityLookup[r.PopUint8()]
+		severity := r.PopUint8()
 		code := r.PopUint32()
 		message := r.PopString()
 		ignoreHeaders(r)
-		log.Println("SERVER MESSAGE", severity, code, message)
+		c.logServerMessage(severity, code, message)
 	default:
 		msg := fmt.Sprintf("unexpected message type: 0x%x", r.MsgType)
 		return &unexpectedMessageError{msg: msg}
@@ -161,11 +182,11 @@ func (c *protocolConnection) fallThrough2pX(r *buff.Reader) error {
 				"got ParameterStatus for unknown parameter %q", name)}
 		}
 	case LogMessage:
-		severity := logMsgSeverityLookup[r.PopUint8()]
+		severity := r.PopUint8()
 		code := r.PopUint32()
 		message := r.PopString()
 		ignoreHeaders(r)
-		log.Println("SERVER MESSAGE", severity, code, message)
+		c.logServerMessage(severity, code, message)
 	default:
 		msg := fmt.Sprintf("unexpected message type: 0x%x", r.MsgType)
 		return &unexpectedMessageError{msg: msg}
@@ -19,6 +19,7 @@ package edgedb
 import (
 	"fmt"
 	"reflect"
+	"time"
 	"unsafe"
 
 	"github.com/edgedb/edgedb-go/internal/buff"
@@ -72,7 +73,9 @@ Retry:
 }
 
 func (c *protocolConnection) pesimistic0pX(r *buff.Reader, q *query) error {
+	start := time.Now()
 	err := c.prepare0pX(r, q)
+	q.recordParse(start, err)
 	if err != nil {
 		return err
 	}
@@ -449,6 +452,7 @@ func decodeDataMsg(
 	q *query,
 	cdcs *codecPair,
 ) (reflect.Value, bool, error) {
+	q.rows++
 	elmCount := r.PopUint16()
 	if elmCount != 1 {
 		return reflect.Value{}, false, fmt.Errorf(
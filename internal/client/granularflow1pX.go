@@ -19,6 +19,7 @@ package edgedb
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/edgedb/edgedb-go/internal/buff"
 	"github.com/edgedb/edgedb-go/internal/codecs"
@@ -46,7 +47,9 @@ func (c *protocolConnection) execGranularFlow1pX(
 }
 
 func (c *protocolConnection) pesimistic1pX(r *buff.Reader, q *query) error {
+	start := time.Now()
 	desc, err := c.parse1pX(r, q)
+	q.recordParse(start, err)
 	if err != nil {
 		return err
 	}
@@ -19,10 +19,12 @@ package edgedb
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/edgedb/edgedb-go/internal/buff"
 	"github.com/edgedb/edgedb-go/internal/codecs"
 	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
 	"github.com/edgedb/edgedb-go/internal/state"
 )
 
@@ -30,39 +32,39 @@ func (c *protocolConnection) execGranularFlow2pX(
 	r *buff.Reader,
 	q *query,
 ) error {
-	var cdcs *codecPair
-	if q.parse {
-		ids, ok := c.getCachedTypeIDs(q)
-		if !ok {
-			return c.pesimistic2pX(r, q)
-		}
-
-		var err error
-		cdcs, err = c.codecsFromIDsV2(ids, q)
-		if err != nil {
-			return err
-		} else if cdcs == nil {
-			return c.pesimistic2pX(r, q)
-		}
-	} else {
-		cdcs = &codecPair{in: codecs.NoOpEncoder, out: codecs.NoOpDecoder}
+	cdcs, err := c.codecs2pX(r, q)
+	if err != nil {
+		return err
 	}
 
 	return c.execute2pX(r, q, cdcs)
 }
 
-func (c *protocolConnection) pesimistic2pX(r *buff.Reader, q *query) error {
-	desc, err := c.parse2pX(r, q)
-	if err != nil {
-		return err
+// codecs2pX returns the codecs for q. If the codecs are not cached the query
+// is parsed first.
+func (c *protocolConnection) codecs2pX(
+	r *buff.Reader,
+	q *query,
+) (*codecPair, error) {
+	if !q.parse {
+		return &codecPair{in: codecs.NoOpEncoder, out: codecs.NoOpDecoder}, nil
 	}
 
-	cdcs, err := c.codecsFromDescriptors2pX(q, desc)
+	if ids, ok := c.getCachedTypeIDs(q); ok {
+		cdcs, err := c.codecsFromIDsV2(ids, q)
+		if err != nil || cdcs != nil {
+			return cdcs, err
+		}
+	}
+
+	start := time.Now()
+	desc, err := c.parse2pX(r, q)
+	q.recordParse(start, err)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return c.execute2pX(r, q, cdcs)
+	return c.codecsFromDescriptors2pX(q, desc)
 }
 
 func (c *protocolConnection) parse2pX(
@@ -189,27 +191,10 @@ func (c *protocolConnection) execute2pX(
 	cdcs *codecPair,
 ) error {
 	w := buff.NewWriter(c.writeMemory[:0])
-	w.BeginMessage(uint8(Execute))
-	w.PushUint16(0) // no headers
-	w.PushUint64(q.capabilities)
-	w.PushUint64(0) // no compilation_flags
-	w.PushUint64(0) // no implicit limit
-	w.PushUint8(uint8(q.fmt))
-	w.PushUint8(uint8(q.expCard))
-	w.PushString(q.cmd)
-	w.PushUUID(c.stateCodec.DescriptorID())
-	err := c.stateCodec.Encode(w, q.state, codecs.Path("state"), false)
+	err := c.encodeExecuteMsg2pX(w, q, cdcs.in, cdcs.out.DescriptorID())
 	if err != nil {
-		return &binaryProtocolError{err: fmt.Errorf(
-			"invalid connection state: %w", err)}
-	}
-
-	w.PushUUID(cdcs.in.DescriptorID())
-	w.PushUUID(cdcs.out.DescriptorID())
-	if e := cdcs.in.Encode(w, q.args, codecs.Path("args"), true); e != nil {
-		return &invalidArgumentError{msg: e.Error()}
+		return err
 	}
-	w.EndMessage()
 
 	w.BeginMessage(uint8(Sync))
 	w.EndMessage()
@@ -283,6 +268,163 @@ func (c *protocolConnection) execute2pX(
 	return err
 }
 
+// execBatchFlow2pX sends an Execute message for each query followed by a
+// single Sync message. If a query fails the server skips the remaining
+// queries.
+func (c *protocolConnection) execBatchFlow2pX(
+	r *buff.Reader,
+	qs []*query,
+	traces []batchTrace,
+	start time.Time,
+) error {
+	cdcs := make([]*codecPair, len(qs))
+	for i, q := range qs {
+		var err error
+		cdcs[i], err = c.codecs2pX(r, q)
+		if err != nil {
+			return err
+		}
+	}
+
+	w := buff.NewWriter(c.writeMemory[:0])
+	for i, q := range qs {
+		outID := cdcs[i].out.DescriptorID()
+		if e := c.encodeExecuteMsg2pX(w, q, cdcs[i].in, outID); e != nil {
+			return e
+		}
+	}
+
+	w.BeginMessage(uint8(Sync))
+	w.EndMessage()
+
+	if e := c.soc.WriteAll(w.Unwrap()); e != nil {
+		return &clientConnectionClosedError{err: e}
+	}
+
+	var err error
+	tmp := make([]reflect.Value, len(qs))
+	found := make([]bool, len(qs))
+	for i, q := range qs {
+		tmp[i] = q.out
+	}
+
+	// i is the index of the query that is currently being read.
+	i := 0
+	done := buff.NewSignal()
+
+	// queryErr collects the errors of the query that is currently being read.
+	var queryErr error
+	fail := func(e error) {
+		err = wrapAll(err, e)
+		queryErr = wrapAll(queryErr, e)
+	}
+
+	for r.Next(done.Chan) {
+		switch Message(r.MsgType) {
+		case StateDataDescription:
+			if e := c.decodeStateDataDescription(r); e != nil {
+				err = wrapAll(err, e)
+			}
+		case CommandDataDescription:
+			descs, e := c.decodeCommandDataDescriptionMsg2pX(r, qs[i])
+			if e != nil {
+				fail(e)
+				break
+			}
+
+			cdcs[i], e = c.codecsFromDescriptors2pX(qs[i], descs)
+			fail(e)
+		case Data:
+			val, ok, e := decodeDataMsg(r, qs[i], cdcs[i])
+			fail(e)
+			if ok {
+				tmp[i] = reflect.Append(tmp[i], val)
+			}
+			found[i] = true
+		case CommandComplete:
+			fail(c.decodeCommandCompleteMsg2pX(qs[i], r))
+			end := time.Now()
+			traces[i] = batchTrace{
+				start: start,
+				end:   end,
+				err:   queryErr,
+				done:  true,
+			}
+			start = end
+			queryErr = nil
+			i++
+		case ReadyForCommand:
+			decodeReadyForCommandMsg(r)
+			done.Signal()
+		case ErrorResponse:
+			var cmd string
+			if i < len(qs) {
+				cmd = qs[i].cmd
+			}
+			err = wrapAll(err, decodeErrorResponseMsg(r, cmd))
+		default:
+			if e := c.fallThrough(r); e != nil {
+				// the connection will not be usable after this x_x
+				return e
+			}
+		}
+	}
+
+	if r.Err != nil {
+		return wrapAll(err, r.Err)
+	}
+
+	// Only the queries that completed have results.
+	for j, q := range qs[:i] {
+		if !q.flat() && q.fmt != Null {
+			q.out.Set(tmp[j])
+		}
+
+		if q.expCard == AtMostOne && !found[j] {
+			if opt, ok := q.out.Addr().Interface().(unseter); ok {
+				opt.Unset()
+			} else {
+				err = wrapAll(err, errZeroResults)
+				traces[j].err = wrapAll(traces[j].err, errZeroResults)
+			}
+		}
+	}
+
+	return err
+}
+
+// encodeExecuteMsg2pX writes an Execute message for q to w.
+func (c *protocolConnection) encodeExecuteMsg2pX(
+	w *buff.Writer,
+	q *query,
+	in codecs.Encoder,
+	outID types.UUID,
+) error {
+	w.BeginMessage(uint8(Execute))
+	w.PushUint16(0) // no headers
+	w.PushUint64(q.capabilities)
+	w.PushUint64(0) // no compilation_flags
+	w.PushUint64(0) // no implicit limit
+	w.PushUint8(uint8(q.fmt))
+	w.PushUint8(uint8(q.expCard))
+	w.PushString(q.cmd)
+	w.PushUUID(c.stateCodec.DescriptorID())
+	err := c.stateCodec.Encode(w, q.state, codecs.Path("state"), false)
+	if err != nil {
+		return &binaryProtocolError{err: fmt.Errorf(
+			"invalid connection state: %w", err)}
+	}
+
+	w.PushUUID(in.DescriptorID())
+	w.PushUUID(outID)
+	if e := in.Encode(w, q.args, codecs.Path("args"), true); e != nil {
+		return &invalidArgumentError{msg: e.Error()}
+	}
+	w.EndMessage()
+
+	return nil
+}
+
 func (c *protocolConnection) codecsFromIDsV2(
 	ids *idPair,
 	q *query,
@@ -406,3 +548,72 @@ func (c *protocolConnection) decodeStateDataDescription2pX(
 	c.stateCodec = codec
 	return nil
 }
+
+// startIter2pX sends an Execute message for q without reading any of the
+// results. The caller is responsible for reading the remaining messages up
+// to and including ReadyForCommand.
+func (c *protocolConnection) startIter2pX(
+	r *buff.Reader,
+	q *query,
+) (*CommandDescriptionV2, error) {
+	descs, ok := c.descriptorsFromCacheV2(q)
+	if !ok {
+		var err error
+		start := time.Now()
+		descs, err = c.parse2pX(r, q)
+		q.recordParse(start, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	in, ok := c.inCodecCache.Get(descs.In.ID)
+	if !ok {
+		var err error
+		in, err = codecs.BuildEncoderV2(&descs.In, c.protocolVersion)
+		if err != nil {
+			return nil, &invalidArgumentError{msg: err.Error()}
+		}
+		c.inCodecCache.Put(descs.In.ID, in)
+	}
+
+	w := buff.NewWriter(c.writeMemory[:0])
+	err := c.encodeExecuteMsg2pX(w, q, in.(codecs.Encoder), descs.Out.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	w.BeginMessage(uint8(Sync))
+	w.EndMessage()
+
+	if e := c.soc.WriteAll(w.Unwrap()); e != nil {
+		return nil, &clientConnectionClosedError{err: e}
+	}
+
+	return descs, nil
+}
+
+func (c *protocolConnection) descriptorsFromCacheV2(
+	q *query,
+) (*CommandDescriptionV2, bool) {
+	ids, ok := c.getCachedTypeIDs(q)
+	if !ok {
+		return nil, false
+	}
+
+	in, ok := descCache.Get(ids.in)
+	if !ok {
+		return nil, false
+	}
+
+	out, ok := descCache.Get(ids.out)
+	if !ok {
+		return nil, false
+	}
+
+	return &CommandDescriptionV2{
+		In:   in.(descriptor.V2),
+		Out:  out.(descriptor.V2),
+		Card: q.expCard,
+	}, true
+}
@@ -0,0 +1,77 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger receives the client's log messages. args are alternating keys and
+// values that add structured attributes to the message. *slog.Logger
+// implements Logger.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// stdLogger is the default Logger. It writes to the standard library's log
+// package.
+type stdLogger struct{}
+
+func (stdLogger) Debug(msg string, args ...interface{}) { logStd(msg, args) }
+func (stdLogger) Info(msg string, args ...interface{})  { logStd(msg, args) }
+func (stdLogger) Warn(msg string, args ...interface{})  { logStd(msg, args) }
+func (stdLogger) Error(msg string, args ...interface{}) { logStd(msg, args) }
+
+func logStd(msg string, args []interface{}) {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+	}
+
+	log.Println(b.String())
+}
+
+// loggerOrDefault returns logger or the default logger if logger is nil.
+func loggerOrDefault(logger Logger) Logger {
+	if logger == nil {
+		return stdLogger{}
+	}
+
+	return logger
+}
+
+// defaultWarningHandler returns a WarningHandler that logs warnings to
+// logger, or LogWarnings if logger is nil.
+func defaultWarningHandler(logger Logger) WarningHandler {
+	if logger == nil {
+		return LogWarnings
+	}
+
+	return func(warnings []error) error {
+		for _, err := range warnings {
+			logger.Warn("EdgeDB warning", "error", err.Error())
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) record(level, msg string, args []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprint(level, " ", msg, args))
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) {
+	l.record("DEBUG", msg, args)
+}
+
+func (l *recordingLogger) Info(msg string, args ...interface{}) {
+	l.record("INFO", msg, args)
+}
+
+func (l *recordingLogger) Warn(msg string, args ...interface{}) {
+	l.record("WARN", msg, args)
+}
+
+func (l *recordingLogger) Error(msg string, args ...interface{}) {
+	l.record("ERROR", msg, args)
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	out := log.Writer()
+	flags := log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+	}()
+
+	stdLogger{}.Warn("server message", "code", 1, "conn_id", 2)
+	assert.Equal(t, "server message code=1 conn_id=2\n", buf.String())
+}
+
+func TestDefaultWarningHandler(t *testing.T) {
+	logger := &recordingLogger{}
+	handler := defaultWarningHandler(logger)
+
+	err := handler([]error{errors.New("a warning")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"WARN EdgeDB warning [error a warning]"},
+		logger.messages)
+}
+
+func TestClientLogsWarnings(t *testing.T) {
+	var hasWarnOnCall bool
+	ctx := context.Background()
+	err := client.QuerySingle(
+		ctx,
+		`
+		SELECT EXISTS (
+			SELECT schema::Function { id }
+			FILTER .name = 'std::_warn_on_call'
+		)
+		`,
+		&hasWarnOnCall,
+	)
+	require.NoError(t, err)
+
+	if !hasWarnOnCall {
+		t.Skip()
+	}
+
+	logger := &recordingLogger{}
+
+	o := opts
+	o.Logger = logger
+	p, err := CreateClient(ctx, o)
+	require.NoError(t, err)
+	defer p.Close() // nolint:errcheck
+
+	err = p.Execute(ctx, "SELECT _warn_on_call()")
+	require.NoError(t, err)
+	require.NotEmpty(t, logger.messages)
+	assert.Regexp(t, "^WARN EdgeDB warning", logger.messages[0])
+}
@@ -17,6 +17,7 @@
 package edgedb
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"time"
@@ -120,6 +121,15 @@ type Options struct {
 	// WarningHandler is invoked when EdgeDB returns warnings. Defaults to
 	// edgedb.LogWarnings.
 	WarningHandler WarningHandler
+
+	// Tracer is notified of the work done by the client.
+	// Tracing is disabled if Tracer is nil.
+	Tracer Tracer
+
+	// Logger receives the client's log messages. If WarningHandler is nil
+	// warnings are also logged to Logger. Defaults to a logger that writes
+	// to the standard library's log package.
+	Logger Logger
 }
 
 // TLSOptions contains the parameters needed to configure TLS on EdgeDB
@@ -169,6 +179,8 @@ type RetryCondition int
 const (
 	// TxConflict indicates that the server could not complete a transaction
 	// because it encountered a deadlock or serialization error.
+	// Use RetryOptions.WithIsolation() to retry conflicts differently
+	// depending on the transaction's isolation level.
 	TxConflict = iota
 
 	// NetworkError indicates that the transaction was interupted
@@ -200,6 +212,14 @@ type RetryRule struct {
 	// backoff determines how long to wait between transaction attempts.
 	// nil indicates that a default function should be used.
 	backoff RetryBackoff
+
+	// maxBackoff is the longest time to wait between attempts.
+	// Zero means there is no limit.
+	maxBackoff time.Duration
+
+	// maxElapsed is the longest time to keep retrying after the first
+	// attempt started. Zero means there is no limit.
+	maxElapsed time.Duration
 }
 
 // WithAttempts sets the rule's attempts. attempts must be greater than zero.
@@ -225,6 +245,56 @@ func (r RetryRule) WithBackoff(fn RetryBackoff) RetryRule {
 	return r
 }
 
+// WithMaxBackoff returns a copy of the RetryRule
+// that waits at most d between attempts. d must be greater than zero.
+func (r RetryRule) WithMaxBackoff(d time.Duration) RetryRule {
+	if d <= 0 {
+		panic(fmt.Sprintf(
+			"RetryRule max backoff must be greater than 0, got %v", d))
+	}
+
+	r.maxBackoff = d
+	return r
+}
+
+// WithMaxElapsed returns a copy of the RetryRule that does not start
+// another attempt once d has elapsed since the first attempt started.
+// d must be greater than zero.
+func (r RetryRule) WithMaxElapsed(d time.Duration) RetryRule {
+	if d <= 0 {
+		panic(fmt.Sprintf(
+			"RetryRule max elapsed time must be greater than 0, got %v", d))
+	}
+
+	r.maxElapsed = d
+	return r
+}
+
+// nextBackoff returns how long to wait after the nth attempt. It returns
+// false if the next attempt would start after the rule's max elapsed time
+// or after ctx's deadline.
+func (r RetryRule) nextBackoff(
+	ctx context.Context,
+	n int,
+	start time.Time,
+) (time.Duration, bool) {
+	backoff := r.backoff(n)
+	if r.maxBackoff > 0 && backoff > r.maxBackoff {
+		backoff = r.maxBackoff
+	}
+
+	next := time.Now().Add(backoff)
+	if r.maxElapsed > 0 && next.Sub(start) > r.maxElapsed {
+		return 0, false
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && next.After(deadline) {
+		return 0, false
+	}
+
+	return backoff, true
+}
+
 // NewRetryOptions returns the default retry options.
 func NewRetryOptions() RetryOptions {
 	return RetryOptions{fromFactory: true}.WithDefault(NewRetryRule())
@@ -237,6 +307,33 @@ type RetryOptions struct {
 	fromFactory bool
 	txConflict  RetryRule
 	network     RetryRule
+
+	// serializable and repeatableRead replace txConflict for transactions
+	// run with that isolation level. They are unset unless their
+	// fromFactory is true.
+	serializable   RetryRule
+	repeatableRead RetryRule
+
+	// predicates are checked in order before the conditions.
+	predicates []retryPredicate
+
+	// onRetry is called before waiting to retry. It may be nil.
+	onRetry RetryObserver
+}
+
+// RetryPredicate reports whether a failed query or transaction
+// should be retried. See RetryOptions.WithPredicate().
+type RetryPredicate func(err Error) bool
+
+// RetryObserver is called each time a query or a transaction
+// is about to be retried.
+// attempt is the number of the attempt that failed with err
+// and wait is how long the client waits before the next attempt.
+type RetryObserver func(attempt int, err error, wait time.Duration)
+
+type retryPredicate struct {
+	match RetryPredicate
+	rule  RetryRule
 }
 
 // WithDefault sets the rule for all conditions to rule.
@@ -271,10 +368,108 @@ func (o RetryOptions) WithCondition( // nolint:gocritic
 	return o
 }
 
-func (o RetryOptions) ruleForException(err Error) (RetryRule, error) {
+// WithIsolation returns a copy of the RetryOptions that uses rule for
+// TxConflict errors in transactions run by Tx() with isolation level i.
+// Transactions using PreferRepeatableRead use the rule for the isolation
+// level they actually ran with. i must be Serializable or RepeatableRead.
+func (o RetryOptions) WithIsolation( // nolint:gocritic
+	i IsolationLevel,
+	rule RetryRule,
+) RetryOptions {
+	if !rule.fromFactory {
+		panic("RetryRule not created with NewRetryRule() is not valid")
+	}
+
+	switch i {
+	case Serializable:
+		o.serializable = rule
+	case RepeatableRead:
+		o.repeatableRead = rule
+	default:
+		panic(fmt.Sprintf("unexpected isolation level: %q", i))
+	}
+
+	return o
+}
+
+// WithPredicate returns a copy of the RetryOptions that retries errors
+// matching fn using rule. Errors that fn matches are retried even if they
+// would not be retried otherwise. Predicates are checked in the order they
+// were added and take precedence over conditions.
+func (o RetryOptions) WithPredicate( // nolint:gocritic
+	fn RetryPredicate,
+	rule RetryRule,
+) RetryOptions {
+	if fn == nil {
+		panic("the retry predicate must not be nil")
+	}
+
+	if !rule.fromFactory {
+		panic("RetryRule not created with NewRetryRule() is not valid")
+	}
+
+	// Copy the predicates so that options derived from o do not share them.
+	predicates := make([]retryPredicate, 0, len(o.predicates)+1)
+	predicates = append(predicates, o.predicates...)
+	o.predicates = append(predicates, retryPredicate{match: fn, rule: rule})
+	return o
+}
+
+// OnRetry returns a copy of the RetryOptions
+// that calls fn before each retry of a query or a transaction.
+func (o RetryOptions) OnRetry(fn RetryObserver) RetryOptions { // nolint:gocritic,lll
+	o.onRetry = fn
+	return o
+}
+
+// shouldRetry returns true if the server indicated that err is retryable
+// or if err matches a predicate.
+func (o RetryOptions) shouldRetry(err Error) bool { // nolint:gocritic
+	if err.HasTag(ShouldRetry) {
+		return true
+	}
+
+	for _, p := range o.predicates {
+		if p.match(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o RetryOptions) notifyRetry( // nolint:gocritic
+	attempt int,
+	err error,
+	wait time.Duration,
+) {
+	if o.onRetry != nil {
+		o.onRetry(attempt, err, wait)
+	}
+}
+
+// ruleForException returns the rule for err in a transaction run with
+// isolation level i. i is empty for queries that are not in a transaction.
+func (o RetryOptions) ruleForException( // nolint:gocritic
+	err Error,
+	i IsolationLevel,
+) (RetryRule, error) {
+	for _, p := range o.predicates {
+		if p.match(err) {
+			return p.rule, nil
+		}
+	}
+
 	switch {
 	case err.Category(TransactionConflictError):
-		return o.txConflict, nil
+		switch {
+		case i == Serializable && o.serializable.fromFactory:
+			return o.serializable, nil
+		case i == RepeatableRead && o.repeatableRead.fromFactory:
+			return o.repeatableRead, nil
+		default:
+			return o.txConflict, nil
+		}
 	case err.Category(ClientError):
 		return o.network, nil
 	default:
@@ -289,8 +484,20 @@ func (o RetryOptions) ruleForException(err Error) (RetryRule, error) {
 type IsolationLevel string
 
 const (
-	// Serializable is the only isolation level
+	// Serializable is the default isolation level.
 	Serializable IsolationLevel = "serializable"
+
+	// RepeatableRead avoids serialization conflicts between transactions
+	// that only read data. Only concurrent writes to the same data conflict.
+	// RepeatableRead requires EdgeDB 6.0 or newer.
+	RepeatableRead IsolationLevel = "repeatable_read"
+
+	// PreferRepeatableRead uses RepeatableRead and falls back to
+	// Serializable if the server reports that a statement is not supported
+	// in repeatable read transactions. Tx() runs the transaction again with
+	// Serializable when any of its statements fails this way, transactions
+	// started with Client.BeginTx() only fall back when they are started.
+	PreferRepeatableRead IsolationLevel = "prefer_repeatable_read"
 )
 
 // NewTxOptions returns the default TxOptions value.
@@ -317,7 +524,9 @@ type TxOptions struct {
 // WithIsolation returns a copy of the TxOptions
 // with the isolation level set to i.
 func (o TxOptions) WithIsolation(i IsolationLevel) TxOptions {
-	if i != Serializable {
+	switch i {
+	case Serializable, RepeatableRead, PreferRepeatableRead:
+	default:
 		panic(fmt.Sprintf("unknown isolation level: %q", i))
 	}
 
@@ -345,6 +554,8 @@ func (o TxOptions) startTxQuery() string { // nolint:gocritic
 	switch o.isolation {
 	case Serializable:
 		query += " ISOLATION SERIALIZABLE"
+	case RepeatableRead, PreferRepeatableRead:
+		query += " ISOLATION REPEATABLE READ"
 	default:
 		panic(fmt.Sprintf("unknown isolation level: %q", o.isolation))
 	}
@@ -389,6 +600,17 @@ func (p Client) WithRetryOptions( // nolint:gocritic
 	return &p
 }
 
+// WithIdempotent returns a shallow copy of the client that retries
+// queries with side effects on the same errors as read only queries,
+// for example when the connection to the server is lost.
+// Only use it for queries that can safely run more than once,
+// like INSERT ... UNLESS CONFLICT upserts.
+// Execute() is retried as well, which it otherwise never is.
+func (p Client) WithIdempotent() *Client { // nolint:gocritic
+	p.idempotent = true
+	return &p
+}
+
 // WithConfig sets configuration values for the returned client.
 func (p Client) WithConfig( // nolint:gocritic
 	cfg map[string]interface{},
@@ -518,14 +740,22 @@ func (p Client) WithoutGlobals(globals ...string) *Client { // nolint:gocritic
 }
 
 // WithWarningHandler sets the warning handler for the returned client. If
-// warningHandler is nil edgedb.LogWarnings is used.
+// warningHandler is nil warnings are logged to Options.Logger
+// or with edgedb.LogWarnings if no logger is set.
 func (p Client) WithWarningHandler( // nolint:gocritic
 	warningHandler WarningHandler,
 ) *Client {
 	if warningHandler == nil {
-		warningHandler = LogWarnings
+		warningHandler = defaultWarningHandler(p.logger)
 	}
 
 	p.warningHandler = warningHandler
 	return &p
 }
+
+// WithTracer sets the tracer for the returned client.
+// If tracer is nil tracing is disabled.
+func (p Client) WithTracer(tracer Tracer) *Client { // nolint:gocritic
+	p.tracer = tracer
+	return &p
+}
@@ -45,6 +45,17 @@ type query struct {
 	state          map[string]interface{}
 	parse          bool
 	warningHandler WarningHandler
+	tracer         Tracer
+
+	// rows is the number of Data messages received for the query.
+	rows int
+
+	// reportedCapabilities are the capabilities the server reported
+	// for the query.
+	reportedCapabilities uint64
+
+	// parseTrace is set if the query was parsed and has a tracer.
+	parseTrace *parseTrace
 }
 
 func (q *query) flat() bool {
@@ -75,6 +86,7 @@ func newQuery(
 	out interface{},
 	parse bool,
 	warningHandler WarningHandler,
+	tracer Tracer,
 ) (*query, error) {
 	var (
 		expCard Cardinality
@@ -93,6 +105,7 @@ func newQuery(
 			state:          state,
 			parse:          parse,
 			warningHandler: warningHandler,
+			tracer:         tracer,
 		}, nil
 	case "Query":
 		expCard = Many
@@ -120,6 +133,7 @@ func newQuery(
 		state:          state,
 		parse:          parse,
 		warningHandler: warningHandler,
+		tracer:         tracer,
 	}
 
 	var err error
@@ -162,6 +176,7 @@ func runQuery(
 	args []interface{},
 	state map[string]interface{},
 	warningHandler WarningHandler,
+	tracer Tracer,
 ) error {
 	if method == "QuerySingleJSON" {
 		switch out.(type) {
@@ -182,6 +197,7 @@ func runQuery(
 		out,
 		true,
 		warningHandler,
+		tracer,
 	)
 	if err != nil {
 		return err
@@ -20,10 +20,12 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"net"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/edgedb/edgedb-go/internal/buff"
 	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -217,6 +219,30 @@ func TestNamedQueryArguments(t *testing.T) {
 	assert.Equal(t, [][]int64{{5, 8}}, result)
 }
 
+func TestNamedQueryArgumentsStruct(t *testing.T) {
+	type Args struct {
+		First  int64               `edgedb:"first"`
+		Second types.OptionalInt64 `edgedb:"second"`
+		Third  types.Opt[int64]    `edgedb:"third"`
+	}
+
+	ctx := context.Background()
+	var result []int64
+	err := client.Query(
+		ctx,
+		`SELECT {
+			<int64>$first,
+			<optional int64>$second,
+			<optional int64>$third,
+		}`,
+		&result,
+		Args{First: 5, Third: types.NewOpt(int64(8))},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5, 8}, result)
+}
+
 func TestNumberedQueryArguments(t *testing.T) {
 	ctx := context.Background()
 	result := [][]int64{}
@@ -232,6 +258,92 @@ func TestNumberedQueryArguments(t *testing.T) {
 	assert.Equal(t, [][]int64{{5, 8}}, result)
 }
 
+func TestPointerQueryArguments(t *testing.T) {
+	ctx := context.Background()
+	name := "Alice"
+	var nickname *string
+	var result []string
+	err := client.Query(
+		ctx,
+		"SELECT {<str>$name, <optional str>$nickname ?? 'none'}",
+		&result,
+		map[string]interface{}{"name": &name, "nickname": nickname},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "none"}, result)
+
+	var str string
+	err = client.QuerySingle(ctx, "SELECT <str>$0", &str, nickname)
+	assert.EqualError(t, err, "edgedb.InvalidArgumentError: "+
+		"cannot encode *string at args[0] because its value is missing")
+}
+
+func TestPointerResults(t *testing.T) {
+	ctx := context.Background()
+	type Result struct {
+		Name     *string    `edgedb:"name"`
+		Nickname *string    `edgedb:"nickname"`
+		Count    *int64     `edgedb:"count"`
+		When     *time.Time `edgedb:"when"`
+		Big      *big.Int   `edgedb:"big"`
+	}
+
+	var result *Result
+	err := client.QuerySingle(
+		ctx,
+		`SELECT {
+			name := 'Alice',
+			nickname := <str>{},
+			count := 3,
+			when := <datetime>{},
+			big := <bigint>{},
+		}`,
+		&result,
+	)
+
+	require.NoError(t, err)
+	name := "Alice"
+	count := int64(3)
+	assert.Equal(t, &Result{Name: &name, Count: &count}, result)
+}
+
+func TestOptQueryArgumentsAndResults(t *testing.T) {
+	ctx := context.Background()
+	type Inner struct {
+		Value int64 `edgedb:"value"`
+	}
+
+	type Result struct {
+		Name     types.Opt[string]     `edgedb:"name"`
+		Nickname types.Opt[string]     `edgedb:"nickname"`
+		Inner    types.Opt[Inner]      `edgedb:"inner"`
+		Missing  types.Opt[Inner]      `edgedb:"missing"`
+		ID       types.Opt[types.UUID] `edgedb:"id"`
+	}
+
+	var result Result
+	err := client.QuerySingle(
+		ctx,
+		`SELECT {
+			name := <optional str>$0,
+			nickname := <optional str>$1,
+			inner := { value := 1 },
+			missing := <tuple<value: int64>>{},
+			id := <uuid>{},
+		}`,
+		&result,
+		types.NewOpt("Alice"),
+		types.Opt[string]{},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, Result{
+		Name:  types.NewOpt("Alice"),
+		Inner: types.NewOpt(Inner{Value: 1}),
+	}, result)
+}
+
 func TestQueryJSON(t *testing.T) {
 	ctx := context.Background()
 	var result []byte
@@ -340,6 +452,58 @@ SELECT { users := (SELECT { a, b } { id, name }) }`,
 	assert.Equal(t, b, "b")
 }
 
+func TestQueryDynamic(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	query := `
+		SELECT {
+			str := 'a',
+			num := 1,
+			tup := (1, 'b'),
+			arr := [1, 2],
+			nothing := <str>{},
+			nested := (SELECT { name := 'c' }),
+		}`
+
+	var maps []map[string]interface{}
+	err := client.Query(ctx, query, &maps)
+	require.NoError(t, err)
+
+	expected := []map[string]interface{}{{
+		"str":     "a",
+		"num":     int64(1),
+		"tup":     []interface{}{int64(1), "b"},
+		"arr":     []interface{}{int64(1), int64(2)},
+		"nothing": nil,
+		"nested":  map[string]interface{}{"name": "c"},
+	}}
+	assert.Equal(t, expected, maps)
+
+	var obj types.Object
+	err = client.QuerySingle(ctx, query, &obj)
+	require.NoError(t, err)
+
+	names := make([]string, len(obj.Fields))
+	for i, field := range obj.Fields {
+		names[i] = field.Name
+	}
+	assert.Equal(t,
+		[]string{"str", "num", "tup", "arr", "nothing", "nested"},
+		names)
+
+	nested, ok := obj.Get("nested")
+	require.True(t, ok)
+	assert.IsType(t, types.Object{}, nested)
+
+	var result interface{}
+	err = client.QuerySingle(ctx, "SELECT <json>[1, 2]", &result)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{float64(1), float64(2)}, result)
+}
+
 func TestError(t *testing.T) {
 	ctx := context.Background()
 	err := client.Execute(ctx, "malformed query;")
@@ -376,6 +540,93 @@ func TestQueryTimesOut(t *testing.T) {
 	assert.Equal(t, int64(2), r)
 }
 
+func TestQueryCanceled(t *testing.T) {
+	p, err := CreateClient(context.Background(), opts)
+	require.NoError(t, err)
+	defer p.Close() // nolint:errcheck
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	var r int64
+	err = p.QuerySingle(
+		ctx,
+		"SELECT count(range_unpack(range(0, 1_000_000_000)))",
+		&r,
+	)
+	require.True(t, errors.Is(err, context.Canceled), err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	var edbErr Error
+	require.True(t, errors.As(err, &edbErr))
+	assert.True(t, edbErr.Category(ClientConnectionError))
+	assert.False(t, edbErr.HasTag(ShouldRetry))
+
+	// The client reconnects after the canceled query's
+	// connection is closed.
+	err = p.QuerySingle(context.Background(), "SELECT 2;", &r)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), r)
+}
+
+func TestAcquireReaderCanceled(t *testing.T) {
+	server, local := net.Pipe()
+	defer server.Close() // nolint:errcheck
+
+	conn := &protocolConnection{
+		soc:                 &autoClosingSocket{conn: local},
+		acquireReaderSignal: make(chan struct{}, 1),
+		readerChan:          make(chan *buff.Reader, 1),
+	}
+	defer conn.soc.Close() // nolint:errcheck
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := conn.acquireReader(ctx)
+	assert.EqualError(t, err, "edgedb: context canceled")
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// The socket was not touched so the connection is not closed.
+	assert.False(t, isClientConnectionError(err))
+	assert.False(t, conn.isClosed())
+}
+
+func TestWatchCancelAfterQueryFinished(t *testing.T) {
+	server, local := net.Pipe()
+	defer server.Close() // nolint:errcheck
+
+	conn := &protocolConnection{soc: &autoClosingSocket{conn: local}}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := conn.watchCancel(ctx)
+
+	// ctx is canceled right after the query finished
+	// but before the watcher is stopped.
+	cancel()
+	require.Eventually(t, conn.isClosed, time.Second, time.Millisecond)
+
+	assert.NoError(t, done(nil))
+	assert.True(t, conn.isClosed())
+
+	// Queries that fail because of the cancellation report it.
+	server2, local2 := net.Pipe()
+	defer server2.Close() // nolint:errcheck
+
+	conn = &protocolConnection{soc: &autoClosingSocket{conn: local2}}
+	ctx, cancel = context.WithCancel(context.Background())
+	done = conn.watchCancel(ctx)
+	cancel()
+	require.Eventually(t, conn.isClosed, time.Second, time.Millisecond)
+
+	err := done(&clientConnectionClosedError{})
+	var edbErr Error
+	require.True(t, errors.As(err, &edbErr))
+	assert.True(t, edbErr.Category(ClientConnectionClosedError))
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
 func TestNilResultValue(t *testing.T) {
 	ctx := context.Background()
 	err := client.Query(ctx, "SELECT 1", nil)
@@ -19,6 +19,8 @@ package edgedb
 import (
 	"context"
 	"errors"
+	"io"
+	"sync/atomic"
 	"time"
 )
 
@@ -27,6 +29,9 @@ type reconnectingConn struct {
 	cacheCollection
 	cfg *connConfig
 
+	// stats are the counters of the Client that owns this connection.
+	stats *clientStats
+
 	// isClosed is true when the connection has been closed by a user.
 	isClosed bool
 }
@@ -51,6 +56,9 @@ func (c *reconnectingConn) reconnect(
 	for {
 		conn, err := connectWithTimeout(ctx, c.cfg, c.cacheCollection)
 		if err == nil {
+			if c.conn != nil {
+				atomic.AddInt64(&c.stats.reconnects, 1)
+			}
 			c.conn = conn
 			return nil
 		}
@@ -96,6 +104,36 @@ func (c *reconnectingConn) granularFlow(
 	return c.borrowableConn.granularFlow(ctx, q)
 }
 
+func (c *reconnectingConn) batchFlow(
+	ctx context.Context,
+	qs []*query,
+) error {
+	if e := c.ensureConnection(ctx); e != nil {
+		return e
+	}
+
+	return c.borrowableConn.batchFlow(ctx, qs)
+}
+
+func (c *reconnectingConn) dumpFlow(ctx context.Context, w io.Writer) error {
+	if e := c.ensureConnection(ctx); e != nil {
+		return e
+	}
+
+	return c.borrowableConn.dumpFlow(ctx, w)
+}
+
+func (c *reconnectingConn) restoreFlow(
+	ctx context.Context,
+	r io.Reader,
+) error {
+	if e := c.ensureConnection(ctx); e != nil {
+		return e
+	}
+
+	return c.borrowableConn.restoreFlow(ctx, r)
+}
+
 // Close closes the connection. Connections are not usable after they are
 // closed.
 func (c *reconnectingConn) Close() (err error) {
@@ -0,0 +1,253 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/codecs"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	"github.com/edgedb/edgedb-go/internal/introspect"
+)
+
+// Rows is an iterator over the results of a query.
+// Use Client.QueryIter() or Tx.QueryIter() to get Rows.
+//
+// Results are decoded one at a time as they are read from the network
+// so the whole result set never needs to be held in memory.
+// The connection used by Rows is not available for other queries
+// until Next returns false or Close is called.
+type Rows struct {
+	// ctx and start are used for tracing.
+	ctx   context.Context
+	start time.Time
+
+	conn *protocolConnection
+	r    *buff.Reader
+	q    *query
+	desc descriptor.V2
+	done *buff.DoneReadingSignal
+
+	// stop stops watching ctx for cancellation.
+	stop func(error) error
+
+	// release is called once the last message has been read.
+	release func(error) error
+
+	hasRow   bool
+	closed   bool
+	err      error
+	closeErr error
+}
+
+func runQueryIter(
+	ctx context.Context,
+	conn *protocolConnection,
+	cmd string,
+	args []interface{},
+	capabilities uint64,
+	state map[string]interface{},
+	warningHandler WarningHandler,
+	tracer Tracer,
+	release func(error) error,
+) (*Rows, error) {
+	if !conn.protocolVersion.GTE(protocolVersion2p0) {
+		return nil, errIterNotSupported
+	}
+
+	q := &query{
+		method:         "QueryIter",
+		cmd:            cmd,
+		fmt:            Binary,
+		expCard:        Many,
+		args:           args,
+		capabilities:   capabilities,
+		state:          state,
+		parse:          true,
+		warningHandler: warningHandler,
+		tracer:         tracer,
+	}
+
+	start := time.Now()
+	r, err := conn.acquireReader(ctx)
+	if err != nil {
+		conn.traceQuery(ctx, q, start, err)
+		return nil, err
+	}
+
+	deadline, _ := ctx.Deadline()
+	if e := conn.soc.SetDeadline(deadline); e != nil {
+		err = firstError(e, conn.releaseReader(r))
+		conn.traceQuery(ctx, q, start, err)
+		return nil, err
+	}
+
+	stop := conn.watchCancel(ctx)
+	descs, err := conn.startIter2pX(r, q)
+	if err != nil {
+		err = firstError(stop(err), conn.releaseReader(r))
+		conn.traceQuery(ctx, q, start, err)
+		return nil, err
+	}
+
+	return &Rows{
+		ctx:     ctx,
+		start:   start,
+		conn:    conn,
+		r:       r,
+		q:       q,
+		desc:    descs.Out,
+		done:    buff.NewSignal(),
+		stop:    stop,
+		release: release,
+	}, nil
+}
+
+// Next prepares the next result for reading with Scan. It returns true on
+// success, or false if there are no more results or an error occurred.
+// Err should be checked after Next returns false.
+func (rows *Rows) Next() bool {
+	if rows.closed {
+		return false
+	}
+
+	if rows.hasRow {
+		rows.r.DiscardMessage()
+		rows.hasRow = false
+	}
+
+	r := rows.r
+	for r.Next(rows.done.Chan) {
+		switch Message(r.MsgType) {
+		case StateDataDescription:
+			if e := rows.conn.decodeStateDataDescription(r); e != nil {
+				rows.err = wrapAll(rows.err, e)
+			}
+		case CommandDataDescription:
+			descs, e := rows.conn.decodeCommandDataDescriptionMsg2pX(
+				r, rows.q)
+			if e != nil {
+				rows.err = wrapAll(rows.err, e)
+			} else {
+				rows.desc = descs.Out
+			}
+		case Data:
+			if rows.err != nil {
+				r.DiscardMessage()
+				continue
+			}
+
+			elmCount := r.PopUint16()
+			if elmCount != 1 {
+				rows.err = fmt.Errorf(
+					"unexpected number of elements: expected 1, got %v",
+					elmCount)
+				r.DiscardMessage()
+				continue
+			}
+
+			rows.q.rows++
+			rows.hasRow = true
+			return true
+		case CommandComplete:
+			e := rows.conn.decodeCommandCompleteMsg2pX(rows.q, r)
+			rows.err = wrapAll(rows.err, e)
+		case ReadyForCommand:
+			decodeReadyForCommandMsg(r)
+			rows.done.Signal()
+		case ErrorResponse:
+			rows.err = wrapAll(
+				rows.err,
+				decodeErrorResponseMsg(r, rows.q.cmd),
+			)
+		default:
+			if e := rows.conn.fallThrough(r); e != nil {
+				// the connection will not be usable after this x_x
+				rows.err = wrapAll(rows.err, e)
+				rows.finish()
+				return false
+			}
+		}
+	}
+
+	rows.finish()
+	return false
+}
+
+func (rows *Rows) finish() {
+	rows.closed = true
+	rows.err = rows.stop(wrapAll(rows.err, rows.r.Err))
+
+	err := rows.conn.releaseReader(rows.r)
+
+	// The connection must not be used after it is released.
+	rows.conn.traceQuery(rows.ctx, rows.q, rows.start, rows.err)
+	rows.closeErr = firstError(err, rows.release(firstError(rows.err, err)))
+}
+
+// Scan decodes the current result into out.
+// out must be a pointer to a value that matches the query's result type,
+// the same as an element of the out argument to Query().
+func (rows *Rows) Scan(out interface{}) error {
+	if !rows.hasRow {
+		return &interfaceError{msg: "Scan called without calling Next"}
+	}
+
+	val, err := introspect.ValueOf(out)
+	if err != nil {
+		return &interfaceError{err: err}
+	}
+
+	key := codecKey{ID: rows.desc.ID, Type: val.Type()}
+	decoder, ok := rows.conn.outCodecCache.Get(key)
+	if !ok {
+		path := codecs.Path(val.Type().String())
+		decoder, err = codecs.BuildDecoderV2(&rows.desc, val.Type(), path)
+		if err != nil {
+			return &invalidArgumentError{msg: fmt.Sprintf(
+				"the \"out\" argument does not match query schema: %v", err)}
+		}
+
+		rows.conn.outCodecCache.Put(key, decoder)
+	}
+
+	elmLen := rows.r.PopUint32()
+	rows.hasRow = false
+	return decoder.(codecs.Decoder).Decode(
+		rows.r.PopSlice(elmLen),
+		unsafe.Pointer(val.UnsafeAddr()),
+	)
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (rows *Rows) Err() error {
+	return rows.err
+}
+
+// Close discards any remaining results and releases the connection.
+// Close is safe to call more than once.
+// If Next returns false the Rows are closed automatically.
+func (rows *Rows) Close() error {
+	for rows.Next() {
+	}
+
+	return rows.closeErr
+}
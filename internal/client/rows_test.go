@@ -0,0 +1,192 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryIter(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	rows, err := client.QueryIter(ctx, "SELECT {1, 2, 3}")
+	require.NoError(t, err)
+
+	var result []int64
+	for rows.Next() {
+		var val int64
+		require.NoError(t, rows.Scan(&val))
+		result = append(result, val)
+	}
+
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	assert.Equal(t, []int64{1, 2, 3}, result)
+}
+
+func TestQueryIterWithArgs(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	rows, err := client.QueryIter(
+		ctx,
+		"SELECT <str>$0 ++ <str>array_unpack(<array<int64>>$1)",
+		"x",
+		[]int64{1, 2},
+	)
+	require.NoError(t, err)
+	defer rows.Close() // nolint:errcheck
+
+	var result []string
+	for rows.Next() {
+		var val string
+		require.NoError(t, rows.Scan(&val))
+		result = append(result, val)
+	}
+
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"x1", "x2"}, result)
+}
+
+func TestQueryIterEarlyClose(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	rows, err := client.QueryIter(ctx, "SELECT range_unpack(range(0, 1000))")
+	require.NoError(t, err)
+
+	require.True(t, rows.Next())
+	require.NoError(t, rows.Close())
+	assert.False(t, rows.Next())
+
+	// The connection must be usable after closing the iterator early.
+	var result int64
+	err = client.QuerySingle(ctx, "SELECT 42", &result)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), result)
+}
+
+func TestQueryIterScanWithoutNext(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	rows, err := client.QueryIter(ctx, "SELECT 1")
+	require.NoError(t, err)
+	defer rows.Close() // nolint:errcheck
+
+	var result int64
+	err = rows.Scan(&result)
+	assert.EqualError(t, err,
+		"edgedb.InterfaceError: Scan called without calling Next")
+}
+
+func TestQueryIterWrongType(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	rows, err := client.QueryIter(ctx, "SELECT {1, 2}")
+	require.NoError(t, err)
+
+	require.True(t, rows.Next())
+	var result string
+	err = rows.Scan(&result)
+	assert.EqualError(t, err, "edgedb.InvalidArgumentError: "+
+		"the \"out\" argument does not match query schema: "+
+		"expected string to be int64 or edgedb.OptionalInt64 got string")
+
+	// Skipping a row that failed to scan must not break iteration.
+	require.True(t, rows.Next())
+	var val int64
+	require.NoError(t, rows.Scan(&val))
+	assert.Equal(t, int64(2), val)
+	require.NoError(t, rows.Close())
+}
+
+func TestQueryIterError(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	rows, err := client.QueryIter(ctx, "SELECT 1 / {1, 0}")
+	require.NoError(t, err)
+
+	for rows.Next() {
+		var val float64
+		require.NoError(t, rows.Scan(&val))
+	}
+
+	var edbErr Error
+	require.True(t, errors.As(rows.Err(), &edbErr), rows.Err())
+	assert.True(t, edbErr.Category(DivisionByZeroError), rows.Err())
+	require.NoError(t, rows.Close())
+}
+
+func TestTxQueryIter(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	var result []int64
+	err := client.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		rows, err := tx.QueryIter(ctx, "SELECT {1, 2, 3}")
+		if err != nil {
+			return err
+		}
+
+		require.True(t, rows.Next())
+		err = tx.Execute(ctx, "SELECT 1")
+		assert.EqualError(t, err, "edgedb.InterfaceError: "+
+			"The connection is borrowed by a query iterator. "+
+			"Close the iterator before running other queries.")
+
+		var val int64
+		require.NoError(t, rows.Scan(&val))
+		result = append(result, val)
+
+		for rows.Next() {
+			require.NoError(t, rows.Scan(&val))
+			result = append(result, val)
+		}
+
+		if e := rows.Err(); e != nil {
+			return e
+		}
+
+		return tx.Execute(ctx, "SELECT 1")
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, result)
+}
@@ -0,0 +1,155 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+var savepointNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// findSavepoint returns the index of the most recently declared savepoint
+// with the given name.
+func (s *txState) findSavepoint(name string) (int, bool) {
+	for i := len(s.savepoints) - 1; i >= 0; i-- {
+		if s.savepoints[i] == name {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func validateSavepointName(name string) error {
+	if !savepointNameRe.MatchString(name) {
+		return &invalidArgumentError{msg: fmt.Sprintf(
+			"invalid savepoint name %q: names must start with a letter "+
+				"or underscore and contain only letters, digits "+
+				"and underscores", name)}
+	}
+
+	return nil
+}
+
+// Savepoint declares a savepoint with the given name.
+// Use RollbackToSavepoint() to undo the work done after the savepoint
+// without rolling back the whole transaction.
+func (t *Tx) Savepoint(ctx context.Context, name string) error {
+	if e := t.assertStarted("declare savepoint"); e != nil {
+		return e
+	}
+
+	if e := validateSavepointName(name); e != nil {
+		return e
+	}
+
+	err := t.executeTxCmd(
+		ctx,
+		"declare savepoint",
+		fmt.Sprintf("DECLARE SAVEPOINT `%v`;", name),
+	)
+	if err != nil {
+		return err
+	}
+
+	t.savepoints = append(t.savepoints, name)
+	return nil
+}
+
+// RollbackToSavepoint undoes the work done since the savepoint was declared.
+// The savepoint remains declared and can be rolled back to again.
+// Savepoints declared after it are released.
+func (t *Tx) RollbackToSavepoint(ctx context.Context, name string) error {
+	if e := t.assertStarted("roll back to savepoint"); e != nil {
+		return e
+	}
+
+	i, ok := t.findSavepoint(name)
+	if !ok {
+		return &interfaceError{msg: fmt.Sprintf(
+			"cannot roll back to savepoint %q; it is not declared", name)}
+	}
+
+	err := t.executeTxCmd(
+		ctx,
+		"rollback to savepoint",
+		fmt.Sprintf("ROLLBACK TO SAVEPOINT `%v`;", name),
+	)
+	if err != nil {
+		return err
+	}
+
+	t.savepoints = t.savepoints[:i+1]
+	return nil
+}
+
+// ReleaseSavepoint releases the savepoint and any savepoints declared after
+// it. The work done since the savepoint was declared is kept.
+func (t *Tx) ReleaseSavepoint(ctx context.Context, name string) error {
+	if e := t.assertStarted("release savepoint"); e != nil {
+		return e
+	}
+
+	i, ok := t.findSavepoint(name)
+	if !ok {
+		return &interfaceError{msg: fmt.Sprintf(
+			"cannot release savepoint %q; it is not declared", name)}
+	}
+
+	err := t.executeTxCmd(
+		ctx,
+		"release savepoint",
+		fmt.Sprintf("RELEASE SAVEPOINT `%v`;", name),
+	)
+	if err != nil {
+		return err
+	}
+
+	t.savepoints = t.savepoints[:i]
+	return nil
+}
+
+// Nested runs action inside of a savepoint. If action returns an error
+// the transaction is rolled back to the savepoint, undoing only the work
+// done by action, and the error is returned. The savepoint is released
+// either way.
+//
+// Unlike Client.Tx(), Nested does not retry action.
+func (t *Tx) Nested(ctx context.Context, action TxBlock) error {
+	t.savepointCount++
+	name := fmt.Sprintf("edgedb_go_nested_%d", t.savepointCount)
+	if e := t.Savepoint(ctx, name); e != nil {
+		return e
+	}
+
+	err := action(ctx, t)
+	if err != nil {
+		if isClientConnectionError(err) {
+			return err
+		}
+
+		if e := t.RollbackToSavepoint(ctx, name); e != nil {
+			return wrapAll(err, e)
+		}
+
+		return wrapAll(err, t.ReleaseSavepoint(ctx, name))
+	}
+
+	return t.ReleaseSavepoint(ctx, name)
+}
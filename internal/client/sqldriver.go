@@ -0,0 +1,566 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/edgedb/edgedb-go/internal/cache"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+)
+
+func init() {
+	sql.Register("edgedb", &sqlDriver{})
+}
+
+// sqlDriver is the database/sql driver registered as "edgedb".
+// The data source name has the same format as the dsn argument
+// to CreateClientDSN().
+type sqlDriver struct{}
+
+func (d *sqlDriver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return connector.Connect(context.Background())
+}
+
+func (d *sqlDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	return NewConnector(dsn, Options{})
+}
+
+// NewConnector returns a database/sql/driver.Connector
+// for use with sql.OpenDB(). The dsn and opts arguments
+// are the same as for CreateClientDSN().
+//
+// Each database/sql connection is a single EdgeDB connection.
+// Query results are decoded dynamically, so querying requires
+// EdgeDB server version 5.0 or greater.
+func NewConnector(dsn string, opts Options) (driver.Connector, error) { // nolint:gocritic,lll
+	cfg, err := parseConnectDSNAndArgs(dsn, &opts, newCfgPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	warningHandler := opts.WarningHandler
+	if warningHandler == nil {
+		warningHandler = defaultWarningHandler(opts.Logger)
+	}
+
+	return &sqlConnector{
+		cfg: cfg,
+		cacheCollection: cacheCollection{
+			serverSettings:    cfg.serverSettings,
+			typeIDCache:       cache.New(1_000),
+			inCodecCache:      cache.New(1_000),
+			outCodecCache:     cache.New(1_000),
+			capabilitiesCache: cache.New(1_000),
+		},
+		warningHandler: warningHandler,
+		tracer:         opts.Tracer,
+		stats:          &clientStats{},
+	}, nil
+}
+
+type sqlConnector struct {
+	cfg *connConfig
+	cacheCollection
+	warningHandler WarningHandler
+	tracer         Tracer
+	stats          *clientStats
+}
+
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn := &reconnectingConn{
+		cfg:             c.cfg,
+		cacheCollection: c.cacheCollection,
+		stats:           c.stats,
+	}
+
+	if err := conn.reconnect(ctx, false); err != nil {
+		return nil, err
+	}
+
+	return &sqlConn{
+		conn:           conn,
+		state:          make(map[string]interface{}),
+		warningHandler: c.warningHandler,
+		tracer:         c.tracer,
+	}, nil
+}
+
+func (c *sqlConnector) Driver() driver.Driver {
+	return &sqlDriver{}
+}
+
+type sqlConn struct {
+	conn           *reconnectingConn
+	state          map[string]interface{}
+	warningHandler WarningHandler
+	tracer         Tracer
+
+	// tx is the transaction in progress or nil.
+	tx *Tx
+}
+
+var (
+	_ driver.Conn               = &sqlConn{}
+	_ driver.ConnBeginTx        = &sqlConn{}
+	_ driver.ConnPrepareContext = &sqlConn{}
+	_ driver.ExecerContext      = &sqlConn{}
+	_ driver.QueryerContext     = &sqlConn{}
+	_ driver.NamedValueChecker  = &sqlConn{}
+	_ driver.Pinger             = &sqlConn{}
+	_ driver.SessionResetter    = &sqlConn{}
+	_ driver.Validator          = &sqlConn{}
+)
+
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqlConn) PrepareContext(
+	_ context.Context,
+	query string,
+) (driver.Stmt, error) {
+	return &sqlStmt{conn: c, query: query}, nil
+}
+
+func (c *sqlConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *sqlConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *sqlConn) BeginTx(
+	ctx context.Context,
+	opts driver.TxOptions,
+) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, &interfaceError{
+			msg: "cannot start; a transaction is already in progress",
+		}
+	}
+
+	txOpts := NewTxOptions().WithReadOnly(opts.ReadOnly)
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault, sql.LevelSerializable:
+	case sql.LevelRepeatableRead:
+		txOpts = txOpts.WithIsolation(RepeatableRead)
+	default:
+		return nil, &invalidArgumentError{msg: fmt.Sprintf(
+			"unsupported isolation level: %v",
+			sql.IsolationLevel(opts.Isolation))}
+	}
+
+	if e := c.conn.ensureConnection(ctx); e != nil {
+		return nil, e
+	}
+
+	conn, err := c.conn.borrow("transaction")
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &Tx{
+		borrowableConn: borrowableConn{conn: conn},
+		txState:        &txState{},
+		options:        txOpts,
+		state:          c.state,
+		warningHandler: c.warningHandler,
+		tracer:         c.tracer,
+		release: func(error) error {
+			c.tx = nil
+			return c.conn.unborrow()
+		},
+	}
+
+	if e := tx.start(ctx); e != nil {
+		return nil, firstError(e, tx.release(e))
+	}
+
+	c.tx = tx
+	return &sqlTx{tx: tx}, nil
+}
+
+func (c *sqlConn) ExecContext(
+	ctx context.Context,
+	cmd string,
+	args []driver.NamedValue,
+) (driver.Result, error) {
+	queryArgs, err := sqlQueryArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.tx != nil {
+		err = c.tx.Execute(ctx, cmd, queryArgs...)
+	} else {
+		var q *query
+		q, err = newQuery(
+			"Execute",
+			cmd,
+			queryArgs,
+			c.conn.capabilities1pX(),
+			c.state,
+			nil,
+			true,
+			c.warningHandler,
+			c.tracer,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.conn.scriptFlow(ctx, q)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.ResultNoRows, nil
+}
+
+func (c *sqlConn) QueryContext(
+	ctx context.Context,
+	cmd string,
+	args []driver.NamedValue,
+) (driver.Rows, error) {
+	queryArgs, err := sqlQueryArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows *Rows
+	if c.tx != nil {
+		rows, err = c.tx.QueryIter(ctx, cmd, queryArgs...)
+	} else {
+		rows, err = c.queryIter(ctx, cmd, queryArgs)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newSQLRows(rows), nil
+}
+
+func (c *sqlConn) queryIter(
+	ctx context.Context,
+	cmd string,
+	args []interface{},
+) (*Rows, error) {
+	if e := c.conn.ensureConnection(ctx); e != nil {
+		return nil, e
+	}
+
+	conn, err := c.conn.borrow("iterator")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := runQueryIter(
+		ctx,
+		conn,
+		cmd,
+		args,
+		c.conn.capabilities1pX(),
+		c.state,
+		c.warningHandler,
+		c.tracer,
+		func(error) error { return c.conn.unborrow() },
+	)
+	if err != nil {
+		return nil, firstError(err, c.conn.unborrow())
+	}
+
+	return rows, nil
+}
+
+// CheckNamedValue accepts all argument values as is.
+// They are encoded the same way as arguments to Client.Query().
+func (c *sqlConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *sqlConn) Ping(ctx context.Context) error {
+	_, err := c.ExecContext(ctx, "SELECT 1;", nil)
+	if isClientConnectionError(err) {
+		return driver.ErrBadConn
+	}
+
+	return err
+}
+
+func (c *sqlConn) ResetSession(context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+
+	return nil
+}
+
+// IsValid returns false if the connection was closed.
+func (c *sqlConn) IsValid() bool {
+	return !c.conn.isClosed && c.conn.conn != nil && !c.conn.conn.isClosed()
+}
+
+// sqlQueryArgs converts database/sql arguments into query arguments.
+// Arguments must be either all positional or all named.
+func sqlQueryArgs(args []driver.NamedValue) ([]interface{}, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	if args[0].Name == "" {
+		positional := make([]interface{}, len(args))
+		for _, arg := range args {
+			if arg.Name != "" {
+				return nil, errMixedSQLArgs
+			}
+			positional[arg.Ordinal-1] = arg.Value
+		}
+
+		return positional, nil
+	}
+
+	named := make(map[string]interface{}, len(args))
+	for _, arg := range args {
+		if arg.Name == "" {
+			return nil, errMixedSQLArgs
+		}
+		named[arg.Name] = arg.Value
+	}
+
+	return []interface{}{named}, nil
+}
+
+var errMixedSQLArgs = &invalidArgumentError{
+	msg: "cannot mix positional and named arguments",
+}
+
+type sqlStmt struct {
+	conn  *sqlConn
+	query string
+}
+
+var (
+	_ driver.Stmt             = &sqlStmt{}
+	_ driver.StmtExecContext  = &sqlStmt{}
+	_ driver.StmtQueryContext = &sqlStmt{}
+)
+
+func (s *sqlStmt) Close() error { return nil }
+
+// NumInput returns -1 because the number of arguments
+// is not known until the query is run.
+func (s *sqlStmt) NumInput() int { return -1 }
+
+func (s *sqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *sqlStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *sqlStmt) ExecContext(
+	ctx context.Context,
+	args []driver.NamedValue,
+) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *sqlStmt) QueryContext(
+	ctx context.Context,
+	args []driver.NamedValue,
+) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+
+	return named
+}
+
+type sqlTx struct {
+	tx *Tx
+}
+
+func (t *sqlTx) Commit() error {
+	return t.tx.Commit(context.Background())
+}
+
+func (t *sqlTx) Rollback() error {
+	return t.tx.Rollback(context.Background())
+}
+
+// sqlRows adapts Rows to driver.Rows. If the query returns objects or
+// named tuples each non implicit field is a column. Otherwise each result
+// is a single column named "result".
+type sqlRows struct {
+	rows    *Rows
+	columns []string
+	shaped  bool
+}
+
+func newSQLRows(rows *Rows) *sqlRows {
+	desc := rows.desc
+	shaped := desc.Type == descriptor.Object ||
+		desc.Type == descriptor.NamedTuple ||
+		(desc.Type == descriptor.Tuple &&
+			len(desc.Fields) > 0 &&
+			desc.Fields[0].Name != "0")
+
+	if !shaped {
+		return &sqlRows{rows: rows, columns: []string{"result"}}
+	}
+
+	columns := make([]string, 0, len(desc.Fields))
+	for _, field := range desc.Fields {
+		if !field.Implicit {
+			columns = append(columns, field.Name)
+		}
+	}
+
+	return &sqlRows{rows: rows, columns: columns, shaped: true}
+}
+
+func (r *sqlRows) Columns() []string {
+	return r.columns
+}
+
+func (r *sqlRows) Close() error {
+	return r.rows.Close()
+}
+
+func (r *sqlRows) Next(dest []driver.Value) error {
+	if !r.rows.Next() {
+		if e := r.rows.Err(); e != nil {
+			return e
+		}
+
+		return io.EOF
+	}
+
+	if !r.shaped {
+		var result interface{}
+		if e := r.rows.Scan(&result); e != nil {
+			return e
+		}
+
+		value, err := sqlValue(result)
+		if err != nil {
+			return err
+		}
+
+		dest[0] = value
+		return nil
+	}
+
+	var result types.Object
+	if e := r.rows.Scan(&result); e != nil {
+		return e
+	}
+
+	i := 0
+	for _, field := range result.Fields {
+		if field.Implicit {
+			continue
+		}
+
+		value, err := sqlValue(field.Value)
+		if err != nil {
+			return err
+		}
+
+		dest[i] = value
+		i++
+	}
+
+	return nil
+}
+
+// sqlValue converts a dynamically decoded value to a driver.Value.
+// Scalars that have no driver.Value equivalent become strings.
+// Collections and objects become JSON.
+func sqlValue(v interface{}) (driver.Value, error) {
+	switch in := v.(type) {
+	case nil, string, []byte, bool, int64, float64, time.Time:
+		return in, nil
+	case int16:
+		return int64(in), nil
+	case int32:
+		return int64(in), nil
+	case float32:
+		return float64(in), nil
+	case fmt.Stringer:
+		return in.String(), nil
+	default:
+		data, err := json.Marshal(sqlJSONValue(v))
+		if err != nil {
+			return nil, &interfaceError{err: err}
+		}
+
+		return data, nil
+	}
+}
+
+// sqlJSONValue replaces objects in v with maps
+// so that v can be marshaled into JSON.
+func sqlJSONValue(v interface{}) interface{} {
+	switch in := v.(type) {
+	case types.Object:
+		out := make(map[string]interface{}, len(in.Fields))
+		for _, field := range in.Fields {
+			if !field.Implicit {
+				out[field.Name] = sqlJSONValue(field.Value)
+			}
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(in))
+		for k, val := range in {
+			out[k] = sqlJSONValue(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(in))
+		for i, val := range in {
+			out[i] = sqlJSONValue(val)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,193 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openSQLDB(t *testing.T) *sql.DB {
+	connector, err := NewConnector("", opts)
+	require.NoError(t, err)
+
+	db := sql.OpenDB(connector)
+	t.Cleanup(func() { assert.NoError(t, db.Close()) })
+	return db
+}
+
+func TestSQLQueryArgs(t *testing.T) {
+	args, err := sqlQueryArgs([]driver.NamedValue{
+		{Ordinal: 2, Value: "b"},
+		{Ordinal: 1, Value: int64(1)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(1), "b"}, args)
+
+	args, err = sqlQueryArgs([]driver.NamedValue{
+		{Name: "a", Ordinal: 1, Value: int64(1)},
+		{Name: "b", Ordinal: 2, Value: "b"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"a": int64(1), "b": "b"},
+	}, args)
+
+	_, err = sqlQueryArgs([]driver.NamedValue{
+		{Name: "a", Ordinal: 1, Value: int64(1)},
+		{Ordinal: 2, Value: "b"},
+	})
+	assert.EqualError(t, err, "edgedb.InvalidArgumentError: "+
+		"cannot mix positional and named arguments")
+}
+
+func TestSQLValue(t *testing.T) {
+	samples := []struct {
+		in  interface{}
+		out driver.Value
+	}{
+		{nil, nil},
+		{int16(1), int64(1)},
+		{int32(2), int64(2)},
+		{float32(0.5), float64(0.5)},
+		{"abc", "abc"},
+		{types.UUID{1}, "01000000-0000-0000-0000-000000000000"},
+		{types.NewLocalDate(2024, 1, 2), "2024-01-02"},
+		{[]interface{}{int64(1), "a"}, []byte(`[1,"a"]`)},
+		{
+			types.Object{Fields: []types.ObjectField{
+				{Name: "id", Value: types.UUID{1}, Implicit: true},
+				{Name: "name", Value: "x"},
+			}},
+			[]byte(`{"name":"x"}`),
+		},
+	}
+
+	for _, s := range samples {
+		out, err := sqlValue(s.in)
+		require.NoError(t, err)
+		assert.Equal(t, s.out, out)
+	}
+}
+
+func TestSQLDriverQuery(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	db := openSQLDB(t)
+
+	rows, err := db.QueryContext(ctx, "SELECT {1, 2, 3} + <int64>$0", 10)
+	require.NoError(t, err)
+
+	columns, err := rows.Columns()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"result"}, columns)
+
+	var result []int64
+	for rows.Next() {
+		var val int64
+		require.NoError(t, rows.Scan(&val))
+		result = append(result, val)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []int64{11, 12, 13}, result)
+
+	var name string
+	var count int64
+	err = db.QueryRowContext(
+		ctx,
+		"SELECT (name := <str>$name, count := <int32>$count)",
+		sql.Named("name", "x"),
+		sql.Named("count", int32(2)),
+	).Scan(&name, &count)
+	require.NoError(t, err)
+	assert.Equal(t, "x", name)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestSQLDriverTx(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	db := openSQLDB(t)
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	_, err = tx.ExecContext(ctx, "INSERT TxTest {name := 'SQL Rollback'}")
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	var count int64
+	err = db.QueryRowContext(
+		ctx,
+		"SELECT count(TxTest FILTER .name = 'SQL Rollback')",
+	).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	_, err = db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	assert.EqualError(t, err, "edgedb.InvalidArgumentError: "+
+		"unsupported isolation level: Read Committed")
+}
+
+func TestSQLDriverScanTypes(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	db := openSQLDB(t)
+
+	var (
+		id       types.UUID
+		date     types.LocalDate
+		duration types.Duration
+		memory   types.Memory
+		str      types.OptionalStr
+	)
+
+	err := db.QueryRowContext(
+		ctx,
+		`SELECT (
+			id := <uuid>'01000000-0000-0000-0000-000000000000',
+			date := <cal::local_date>$0,
+			duration := <duration>'1 hour',
+			memory := <cfg::memory>'2KiB',
+			str := 'abc',
+		)`,
+		types.NewLocalDate(2024, 1, 2),
+	).Scan(&id, &date, &duration, &memory, &str)
+	require.NoError(t, err)
+	assert.Equal(t, types.UUID{1}, id)
+	assert.Equal(t, types.NewLocalDate(2024, 1, 2), date)
+	assert.Equal(t, types.Duration(3_600_000_000), duration)
+	assert.Equal(t, types.Memory(2048), memory)
+	assert.Equal(t, types.NewOptionalStr("abc"), str)
+}
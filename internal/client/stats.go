@@ -0,0 +1,100 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ClientStats is a snapshot of a Client's connection pool metrics.
+// Counters are cumulative over the lifetime of the Client.
+type ClientStats struct {
+	// MaxConnections is the maximum number of connections the pool will
+	// open. It is zero until the first connection is made.
+	MaxConnections int
+
+	// OpenConnections is the number of open connections,
+	// both in use and idle.
+	OpenConnections int
+
+	// InUse is the number of connections currently acquired from the pool.
+	InUse int
+
+	// Idle is the number of open connections waiting in the pool.
+	Idle int
+
+	// AcquireCount is the total number of connections acquired from the
+	// pool.
+	AcquireCount int64
+
+	// AcquireDuration is the total time spent waiting to acquire
+	// connections from the pool, including the time spent connecting
+	// new connections.
+	AcquireDuration time.Duration
+
+	// IdleClosed is the total number of connections closed because they
+	// were idle for longer than the server's session_idle_timeout.
+	IdleClosed int64
+
+	// Reconnects is the total number of times a lost connection
+	// was re-established.
+	Reconnects int64
+
+	// Retries is the total number of times a query or transaction
+	// was retried.
+	Retries int64
+}
+
+// clientStats holds the counters for a Client. The counters are shared by
+// all copies of a Client and are updated atomically.
+type clientStats struct {
+	acquireCount    int64
+	acquireDuration int64
+	idleClosed      int64
+	reconnects      int64
+	retries         int64
+	open            int64
+	inUse           int64
+	maxConns        int64
+}
+
+// Stats returns a snapshot of the client's connection pool metrics.
+func (p *Client) Stats() ClientStats {
+	s := p.stats
+	open := atomic.LoadInt64(&s.open)
+	inUse := atomic.LoadInt64(&s.inUse)
+	idle := open - inUse
+	if idle < 0 {
+		// The counters are read separately
+		// and may be briefly out of sync.
+		idle = 0
+	}
+
+	return ClientStats{
+		MaxConnections:  int(atomic.LoadInt64(&s.maxConns)),
+		OpenConnections: int(open),
+		InUse:           int(inUse),
+		Idle:            int(idle),
+		AcquireCount:    atomic.LoadInt64(&s.acquireCount),
+		AcquireDuration: time.Duration(
+			atomic.LoadInt64(&s.acquireDuration)),
+		IdleClosed: atomic.LoadInt64(&s.idleClosed),
+		Reconnects: atomic.LoadInt64(&s.reconnects),
+		Retries:    atomic.LoadInt64(&s.retries),
+	}
+}
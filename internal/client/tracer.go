@@ -0,0 +1,228 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Tracer observes the work done by a Client. Events are reported after the
+// work is finished. Each event records when the work started and how long
+// it took so that it can be recorded as a span by a tracing library.
+//
+// Tracer methods are called synchronously while the connection is held
+// and must be safe for concurrent use.
+type Tracer interface {
+	// TraceAcquire is called after acquiring a connection from the pool.
+	TraceAcquire(context.Context, AcquireEvent)
+
+	// TraceParse is called after the server parses a query.
+	// Queries are only parsed when their type descriptors are not cached.
+	TraceParse(context.Context, QueryEvent)
+
+	// TraceQuery is called after each attempt to run a query.
+	TraceQuery(context.Context, QueryEvent)
+
+	// TraceRetry is called before retrying a failed query or transaction.
+	TraceRetry(context.Context, RetryEvent)
+
+	// TraceTx is called after each transaction control command.
+	TraceTx(context.Context, TxEvent)
+}
+
+// AcquireEvent describes acquiring a connection from the pool.
+type AcquireEvent struct {
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// QueryEvent describes parsing or running a query.
+type QueryEvent struct {
+	// Method is the name of the method that ran the query,
+	// for example "QuerySingle".
+	Method string
+
+	// Query is the query text.
+	Query string
+
+	// Capabilities are the capabilities the server reported for the query.
+	// It is zero if the server did not report the query's capabilities.
+	Capabilities uint64
+
+	// Cardinality is the expected cardinality of the query's result,
+	// either "Many" or "AtMostOne".
+	Cardinality string
+
+	// Rows is the number of results received. It is always zero for parse
+	// events.
+	Rows int
+
+	// ProtocolVersion is the protocol version of the connection,
+	// for example "2.0".
+	ProtocolVersion string
+
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// RetryEvent describes a retry of a failed query or transaction.
+type RetryEvent struct {
+	// Attempt is the number of the attempt that failed, starting at 1.
+	Attempt int
+
+	// Backoff is the time to wait before the next attempt.
+	Backoff time.Duration
+
+	// Err is the error that caused the retry.
+	Err error
+}
+
+// TxEvent describes a transaction control command.
+type TxEvent struct {
+	// Action is one of "start", "commit", "rollback", "declare savepoint",
+	// "rollback to savepoint" or "release savepoint".
+	Action string
+
+	// Query is the command that was run.
+	Query string
+
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// parseTrace records a parse so that it can be reported
+// once the query is finished.
+type parseTrace struct {
+	start    time.Time
+	duration time.Duration
+	err      error
+}
+
+// batchTrace records how one query in a batch ran. Queries in a batch run
+// one after another so a query starts when the previous one completes.
+type batchTrace struct {
+	start time.Time
+	end   time.Time
+	err   error
+	// done is true if the query completed.
+	done bool
+}
+
+func (q *query) recordParse(start time.Time, err error) {
+	if q.tracer == nil {
+		return
+	}
+
+	q.parseTrace = &parseTrace{
+		start:    start,
+		duration: time.Since(start),
+		err:      err,
+	}
+}
+
+func (c *protocolConnection) traceQuery(
+	ctx context.Context,
+	q *query,
+	start time.Time,
+	err error,
+) {
+	c.traceQueryDuration(ctx, q, start, time.Since(start), err)
+}
+
+func (c *protocolConnection) traceQueryDuration(
+	ctx context.Context,
+	q *query,
+	start time.Time,
+	duration time.Duration,
+	err error,
+) {
+	if q.tracer == nil {
+		return
+	}
+
+	event := QueryEvent{
+		Method:       q.method,
+		Query:        q.cmd,
+		Capabilities: q.reportedCapabilities,
+		Cardinality:  q.expCard.String(),
+		ProtocolVersion: fmt.Sprintf(
+			"%v.%v",
+			c.protocolVersion.Major,
+			c.protocolVersion.Minor,
+		),
+	}
+
+	if p := q.parseTrace; p != nil {
+		q.parseTrace = nil
+		parse := event
+		parse.Start = p.start
+		parse.Duration = p.duration
+		parse.Err = p.err
+		q.tracer.TraceParse(ctx, parse)
+	}
+
+	event.Rows = q.rows
+	event.Start = start
+	event.Duration = duration
+	event.Err = err
+	q.tracer.TraceQuery(ctx, event)
+}
+
+// traceBatch traces each query in a batch with its own timing and error.
+// A query that did not complete either failed or did not run because an
+// earlier query failed so it gets the batch's error.
+func (c *protocolConnection) traceBatch(
+	ctx context.Context,
+	qs []*query,
+	traces []batchTrace,
+	start time.Time,
+	err error,
+) {
+	end := time.Now()
+	for i, q := range qs {
+		t := traces[i]
+		if !t.done {
+			t = batchTrace{start: start, end: end, err: err}
+		}
+
+		c.traceQueryDuration(ctx, q, t.start, t.end.Sub(t.start), t.err)
+		start = t.end
+	}
+}
+
+func traceRetry(
+	ctx context.Context,
+	tracer Tracer,
+	attempt int,
+	backoff time.Duration,
+	err error,
+) {
+	if tracer == nil {
+		return
+	}
+
+	tracer.TraceRetry(ctx, RetryEvent{
+		Attempt: attempt,
+		Backoff: backoff,
+		Err:     err,
+	})
+}
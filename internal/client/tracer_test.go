@@ -0,0 +1,183 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTracer struct {
+	mu      sync.Mutex
+	acquire []AcquireEvent
+	parse   []QueryEvent
+	query   []QueryEvent
+	retry   []RetryEvent
+	tx      []TxEvent
+}
+
+func (t *recordingTracer) TraceAcquire(_ context.Context, e AcquireEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.acquire = append(t.acquire, e)
+}
+
+func (t *recordingTracer) TraceParse(_ context.Context, e QueryEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.parse = append(t.parse, e)
+}
+
+func (t *recordingTracer) TraceQuery(_ context.Context, e QueryEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.query = append(t.query, e)
+}
+
+func (t *recordingTracer) TraceRetry(_ context.Context, e RetryEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.retry = append(t.retry, e)
+}
+
+func (t *recordingTracer) TraceTx(_ context.Context, e TxEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tx = append(t.tx, e)
+}
+
+func TestTracerQuery(t *testing.T) {
+	ctx := context.Background()
+	tracer := &recordingTracer{}
+	c := client.WithTracer(tracer)
+
+	// Use a unique query so that it is not already in the query cache.
+	query := "SELECT {1, 2, 3} # TestTracerQuery"
+	var result []int64
+	require.NoError(t, c.Query(ctx, query, &result))
+
+	require.Equal(t, 1, len(tracer.acquire))
+	assert.NoError(t, tracer.acquire[0].Err)
+
+	require.Equal(t, 1, len(tracer.parse))
+	assert.Equal(t, query, tracer.parse[0].Query)
+	assert.Equal(t, 0, tracer.parse[0].Rows)
+
+	require.Equal(t, 1, len(tracer.query))
+	event := tracer.query[0]
+	assert.Equal(t, "Query", event.Method)
+	assert.Equal(t, query, event.Query)
+	assert.Equal(t, "Many", event.Cardinality)
+	assert.Equal(t, 3, event.Rows)
+	assert.Equal(t, uint64(0), event.Capabilities)
+	assert.NotEmpty(t, event.ProtocolVersion)
+	assert.False(t, event.Start.IsZero())
+	assert.NoError(t, event.Err)
+
+	// The second run uses the cached descriptors.
+	require.NoError(t, c.Query(ctx, query, &result))
+	assert.Equal(t, 1, len(tracer.parse))
+	assert.Equal(t, 2, len(tracer.query))
+}
+
+func TestTracerQueryError(t *testing.T) {
+	ctx := context.Background()
+	tracer := &recordingTracer{}
+	c := client.WithTracer(tracer)
+
+	var result int64
+	err := c.QuerySingle(ctx, "SELECT 1 // 0", &result)
+	require.Error(t, err)
+
+	require.Equal(t, 1, len(tracer.query))
+	assert.Equal(t, "AtMostOne", tracer.query[0].Cardinality)
+	assert.Equal(t, err, tracer.query[0].Err)
+}
+
+func TestTracerTx(t *testing.T) {
+	ctx := context.Background()
+	tracer := &recordingTracer{}
+	c := client.WithTracer(tracer)
+
+	err := c.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		return tx.Execute(ctx, "SELECT 1")
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(tracer.tx))
+	assert.Equal(t, "start", tracer.tx[0].Action)
+	assert.Equal(t, "commit", tracer.tx[1].Action)
+	assert.Equal(t, "COMMIT;", tracer.tx[1].Query)
+
+	err = c.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		return errors.New("user defined error")
+	})
+	require.EqualError(t, err, "user defined error")
+	require.Equal(t, 4, len(tracer.tx))
+	assert.Equal(t, "rollback", tracer.tx[3].Action)
+
+	// Transaction control commands are not reported as queries.
+	require.Equal(t, 1, len(tracer.query))
+	assert.Equal(t, "SELECT 1", tracer.query[0].Query)
+}
+
+func TestTraceBatchUsesEachQuerysTimingAndError(t *testing.T) {
+	tracer := &recordingTracer{}
+	qs := []*query{
+		{tracer: tracer, cmd: "SELECT 1"},
+		{tracer: tracer, cmd: "SELECT 2"},
+		{tracer: tracer, cmd: "SELECT 3"},
+		{tracer: tracer, cmd: "SELECT 4"},
+	}
+
+	start := time.Now().Add(-time.Minute)
+	first := start.Add(time.Second)
+	second := first.Add(2 * time.Second)
+	queryErr := errors.New("zero results")
+	batchErr := errors.New("query failed")
+	traces := []batchTrace{
+		{start: start, end: first, done: true},
+		{start: first, end: second, err: queryErr, done: true},
+		{},
+		{},
+	}
+
+	c := &protocolConnection{}
+	c.traceBatch(context.Background(), qs, traces, start, batchErr)
+
+	require.Equal(t, 4, len(tracer.query))
+	assert.Equal(t, start, tracer.query[0].Start)
+	assert.Equal(t, time.Second, tracer.query[0].Duration)
+	assert.NoError(t, tracer.query[0].Err)
+
+	assert.Equal(t, first, tracer.query[1].Start)
+	assert.Equal(t, 2*time.Second, tracer.query[1].Duration)
+	assert.Equal(t, queryErr, tracer.query[1].Err)
+
+	// The failed query starts when the previous query completed.
+	assert.Equal(t, second, tracer.query[2].Start)
+	assert.Equal(t, batchErr, tracer.query[2].Err)
+	assert.Equal(t, batchErr, tracer.query[3].Err)
+	assert.True(t, tracer.query[3].Start.After(second))
+	assert.Equal(t, time.Duration(0), tracer.query[3].Duration)
+}
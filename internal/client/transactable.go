@@ -19,6 +19,8 @@ package edgedb
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -26,14 +28,49 @@ type transactableConn struct {
 	*reconnectingConn
 	txOpts    TxOptions
 	retryOpts RetryOptions
+
+	// idempotent is true if queries can be retried
+	// even if they have side effects.
+	idempotent bool
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("edgedb: %w", ctx.Err())
+	}
 }
 
 func (c *transactableConn) granularFlow(ctx context.Context, q *query) error {
+	return c.retryFlow(ctx, q, c.reconnectingConn.granularFlow)
+}
+
+// scriptFlow only retries scripts if the connection is idempotent.
+func (c *transactableConn) scriptFlow(ctx context.Context, q *query) error {
+	if !c.idempotent {
+		return c.reconnectingConn.scriptFlow(ctx, q)
+	}
+
+	return c.retryFlow(ctx, q, c.reconnectingConn.scriptFlow)
+}
+
+func (c *transactableConn) retryFlow(
+	ctx context.Context,
+	q *query,
+	flow func(context.Context, *query) error,
+) error {
 	var (
 		err    error
 		edbErr Error
 	)
 
+	start := time.Now()
 	for i := 1; true; i++ {
 		if errors.As(err, &edbErr) && c.conn.soc.Closed() {
 			err = c.reconnect(ctx, true)
@@ -42,19 +79,21 @@ func (c *transactableConn) granularFlow(ctx context.Context, q *query) error {
 			}
 		}
 
-		err = c.reconnectingConn.granularFlow(ctx, q)
+		err = flow(ctx, q)
 
 	Error:
 		// q is a read only query if it has no capabilities
 		// i.e. capabilities == 0. Read only queries are always
 		// retryable, mutation queries are retryable if the
-		// error explicitly indicates a transaction conflict.
+		// error explicitly indicates a transaction conflict
+		// or if the connection is idempotent.
 		capabilities, ok := c.getCachedCapabilities(q)
-		if ok &&
-			errors.As(err, &edbErr) &&
-			edbErr.HasTag(ShouldRetry) &&
-			(capabilities == 0 || edbErr.Category(TransactionConflictError)) {
-			rule, e := c.retryOpts.ruleForException(edbErr)
+		if errors.As(err, &edbErr) &&
+			c.retryOpts.shouldRetry(edbErr) &&
+			(c.idempotent || (ok && (capabilities == 0 ||
+				edbErr.Category(TransactionConflictError)))) {
+			// Queries outside of Tx() do not have an isolation level.
+			rule, e := c.retryOpts.ruleForException(edbErr, "")
 			if e != nil {
 				return e
 			}
@@ -63,7 +102,17 @@ func (c *transactableConn) granularFlow(ctx context.Context, q *query) error {
 				return err
 			}
 
-			time.Sleep(rule.backoff(i))
+			backoff, ok := rule.nextBackoff(ctx, i, start)
+			if !ok {
+				return err
+			}
+
+			atomic.AddInt64(&c.stats.retries, 1)
+			traceRetry(ctx, q.tracer, i, backoff, err)
+			c.retryOpts.notifyRetry(i, err, backoff)
+			if e := sleep(ctx, backoff); e != nil {
+				return wrapAll(err, e)
+			}
 			continue
 		}
 
@@ -78,6 +127,7 @@ func (c *transactableConn) tx(
 	action TxBlock,
 	state map[string]interface{},
 	warningHandler WarningHandler,
+	tracer Tracer,
 ) (err error) {
 	conn, err := c.borrow("transaction")
 	if err != nil {
@@ -86,6 +136,9 @@ func (c *transactableConn) tx(
 	defer func() { err = firstError(err, c.unborrow()) }()
 
 	var edbErr Error
+	txOpts := c.txOpts
+	isolation := txOpts.isolation
+	start := time.Now()
 	for i := 1; true; i++ {
 		if errors.As(err, &edbErr) && c.conn.soc.Closed() {
 			err = c.reconnect(ctx, true)
@@ -100,11 +153,13 @@ func (c *transactableConn) tx(
 			tx := &Tx{
 				borrowableConn: borrowableConn{conn: conn},
 				txState:        &txState{},
-				options:        c.txOpts,
+				options:        txOpts,
 				state:          state,
 				warningHandler: warningHandler,
+				tracer:         tracer,
 			}
 			err = tx.start(ctx)
+			isolation = tx.isolation
 			if err != nil {
 				goto Error
 			}
@@ -112,9 +167,9 @@ func (c *transactableConn) tx(
 			err = action(ctx, tx)
 			if err == nil {
 				err = tx.commit(ctx)
-				if errors.As(err, &edbErr) &&
-					edbErr.Category(TransactionError) &&
-					edbErr.HasTag(ShouldRetry) {
+				// A commit that failed because the connection was lost
+				// may have been applied, so it is never retried.
+				if err != nil && !isClientConnectionError(err) {
 					goto Error
 				}
 				return err
@@ -128,8 +183,18 @@ func (c *transactableConn) tx(
 		}
 
 	Error:
-		if errors.As(err, &edbErr) && edbErr.HasTag(ShouldRetry) {
-			rule, e := c.retryOpts.ruleForException(edbErr)
+		// A statement that can not run in a repeatable read transaction
+		// is run again right away in a serializable transaction.
+		if txOpts.isolation == PreferRepeatableRead &&
+			isolation == RepeatableRead &&
+			isRepeatableReadUnsupported(err) {
+			txOpts = txOpts.WithIsolation(Serializable)
+			i--
+			continue
+		}
+
+		if errors.As(err, &edbErr) && c.retryOpts.shouldRetry(edbErr) {
+			rule, e := c.retryOpts.ruleForException(edbErr, isolation)
 			if e != nil {
 				return e
 			}
@@ -138,7 +203,17 @@ func (c *transactableConn) tx(
 				return err
 			}
 
-			time.Sleep(rule.backoff(i))
+			backoff, ok := rule.nextBackoff(ctx, i, start)
+			if !ok {
+				return err
+			}
+
+			atomic.AddInt64(&c.stats.retries, 1)
+			traceRetry(ctx, tracer, i, backoff, err)
+			c.retryOpts.notifyRetry(i, err, backoff)
+			if e := sleep(ctx, backoff); e != nil {
+				return wrapAll(err, e)
+			}
 			continue
 		}
 
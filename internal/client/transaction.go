@@ -19,6 +19,7 @@ package edgedb
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // TxBlock is work to be done in a transaction.
@@ -36,6 +37,13 @@ const (
 
 type txState struct {
 	txStatus txStatus
+
+	// savepoints are the names of the declared savepoints
+	// in the order they were declared.
+	savepoints []string
+
+	// savepointCount is used to generate savepoint names for Tx.Nested().
+	savepointCount int
 }
 
 // assertNotDone returns an error if the transaction is in a done state.
@@ -72,13 +80,23 @@ func (s *txState) assertStarted(opName string) error {
 	}
 }
 
-// Tx is a transaction. Use Client.Tx() to get a transaction.
+// Tx is a transaction. Use Client.Tx() or Client.BeginTx()
+// to get a transaction.
 type Tx struct {
 	borrowableConn
 	*txState
 	options        TxOptions
 	state          map[string]interface{}
 	warningHandler WarningHandler
+	tracer         Tracer
+
+	// isolation is the isolation level the transaction was started with.
+	// It is never PreferRepeatableRead.
+	isolation IsolationLevel
+
+	// release returns the connection once an explicit transaction is
+	// finished. It is nil for transactions run by Client.Tx().
+	release func(error) error
 }
 
 func (t *Tx) execute(
@@ -86,6 +104,48 @@ func (t *Tx) execute(
 	cmd string,
 	sucessState txStatus,
 ) error {
+	var action string
+	switch sucessState {
+	case startedTx:
+		action = "start"
+	case committedTx:
+		action = "commit"
+	case rolledBackTx:
+		action = "rollback"
+	}
+
+	err := t.executeTxCmd(ctx, action, cmd)
+
+	switch err {
+	case nil:
+		t.txStatus = sucessState
+	default:
+		t.txStatus = failedTx
+	}
+
+	return err
+}
+
+// executeTxCmd runs a transaction control command
+// without changing the transaction status.
+func (t *Tx) executeTxCmd(
+	ctx context.Context,
+	action string,
+	cmd string,
+) (err error) {
+	if t.tracer != nil {
+		start := time.Now()
+		defer func() {
+			t.tracer.TraceTx(ctx, TxEvent{
+				Action:   action,
+				Query:    cmd,
+				Start:    start,
+				Duration: time.Since(start),
+				Err:      err,
+			})
+		}()
+	}
+
 	q, err := newQuery(
 		"Execute",
 		cmd,
@@ -95,21 +155,13 @@ func (t *Tx) execute(
 		nil,
 		false,
 		t.warningHandler,
+		nil, // reported by TraceTx instead of TraceQuery
 	)
 	if err != nil {
 		return err
 	}
 
-	err = t.borrowableConn.scriptFlow(ctx, q)
-
-	switch err {
-	case nil:
-		t.txStatus = sucessState
-	default:
-		t.txStatus = failedTx
-	}
-
-	return err
+	return t.borrowableConn.scriptFlow(ctx, q)
 }
 
 func (t *Tx) start(ctx context.Context) error {
@@ -123,7 +175,23 @@ func (t *Tx) start(ctx context.Context) error {
 		}
 	}
 
-	query := t.options.startTxQuery()
+	t.isolation = t.options.isolation
+	if t.isolation == PreferRepeatableRead {
+		t.isolation = RepeatableRead
+	}
+
+	query := t.options.WithIsolation(t.isolation).startTxQuery()
+	err := t.execute(ctx, query, startedTx)
+	if err == nil ||
+		t.options.isolation != PreferRepeatableRead ||
+		!isRepeatableReadUnsupported(err) {
+		return err
+	}
+
+	// The server does not allow repeatable read for this transaction.
+	t.txStatus = newTx
+	t.isolation = Serializable
+	query = t.options.WithIsolation(Serializable).startTxQuery()
 	return t.execute(ctx, query, startedTx)
 }
 
@@ -143,6 +211,38 @@ func (t *Tx) rollback(ctx context.Context) error {
 	return t.execute(ctx, "ROLLBACK;", rolledBackTx)
 }
 
+// Commit commits the transaction and releases its connection.
+// Commit can only be called on transactions started with Client.BeginTx().
+func (t *Tx) Commit(ctx context.Context) error {
+	return t.finish(ctx, "commit", t.commit)
+}
+
+// Rollback rolls back the transaction and releases its connection.
+// Rollback can only be called on transactions started with
+// Client.BeginTx().
+func (t *Tx) Rollback(ctx context.Context) error {
+	return t.finish(ctx, "rollback", t.rollback)
+}
+
+func (t *Tx) finish(
+	ctx context.Context,
+	opName string,
+	action func(context.Context) error,
+) error {
+	if t.release == nil {
+		return &interfaceError{msg: fmt.Sprintf(
+			"cannot %v; the transaction is managed by Client.Tx()", opName,
+		)}
+	}
+
+	if e := t.assertStarted(opName); e != nil {
+		return e
+	}
+
+	err := action(ctx)
+	return firstError(err, t.release(err))
+}
+
 func (t *Tx) scriptFlow(ctx context.Context, q *query) error {
 	if e := t.assertStarted("Execute"); e != nil {
 		return e
@@ -159,6 +259,14 @@ func (t *Tx) granularFlow(ctx context.Context, q *query) error {
 	return t.borrowableConn.granularFlow(ctx, q)
 }
 
+func (t *Tx) batchFlow(ctx context.Context, qs []*query) error {
+	if e := t.assertStarted("Batch"); e != nil {
+		return e
+	}
+
+	return t.borrowableConn.batchFlow(ctx, qs)
+}
+
 // Execute an EdgeQL command (or commands).
 func (t *Tx) Execute(
 	ctx context.Context,
@@ -174,6 +282,7 @@ func (t *Tx) Execute(
 		nil,
 		true,
 		t.warningHandler,
+		t.tracer,
 	)
 	if err != nil {
 		return err
@@ -198,9 +307,45 @@ func (t *Tx) Query(
 		args,
 		t.state,
 		t.warningHandler,
+		t.tracer,
 	)
 }
 
+// QueryIter runs a query and returns an iterator over its results.
+// No other queries can be run in the transaction
+// until the iterator is closed.
+func (t *Tx) QueryIter(
+	ctx context.Context,
+	cmd string,
+	args ...interface{},
+) (*Rows, error) {
+	if e := t.assertStarted("QueryIter"); e != nil {
+		return nil, e
+	}
+
+	conn, err := t.borrow("iterator")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := runQueryIter(
+		ctx,
+		conn,
+		cmd,
+		args,
+		t.capabilities1pX(),
+		t.state,
+		t.warningHandler,
+		t.tracer,
+		func(error) error { return t.unborrow() },
+	)
+	if err != nil {
+		return nil, firstError(err, t.unborrow())
+	}
+
+	return rows, nil
+}
+
 // QuerySingle runs a singleton-returning query and returns its element.
 // If the query executes successfully but doesn't return a result
 // a NoDataError is returned. If the out argument is an optional type the out
@@ -220,6 +365,7 @@ func (t *Tx) QuerySingle(
 		args,
 		t.state,
 		t.warningHandler,
+		t.tracer,
 	)
 }
 
@@ -239,6 +385,7 @@ func (t *Tx) QueryJSON(
 		args,
 		t.state,
 		t.warningHandler,
+		t.tracer,
 	)
 }
 
@@ -260,5 +407,6 @@ func (t *Tx) QuerySingleJSON(
 		args,
 		t.state,
 		t.warningHandler,
+		t.tracer,
 	)
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
 	"github.com/stretchr/testify/assert"
@@ -160,6 +161,155 @@ func TestTxKinds(t *testing.T) {
 	}
 }
 
+func TestTxStartQuery(t *testing.T) {
+	samples := []struct {
+		opts     TxOptions
+		expected string
+	}{
+		{
+			NewTxOptions(),
+			"START TRANSACTION ISOLATION SERIALIZABLE, " +
+				"READ WRITE, NOT DEFERRABLE;",
+		},
+		{
+			newTxOpts(RepeatableRead, true, false),
+			"START TRANSACTION ISOLATION REPEATABLE READ, " +
+				"READ ONLY, NOT DEFERRABLE;",
+		},
+		{
+			newTxOpts(PreferRepeatableRead, false, true),
+			"START TRANSACTION ISOLATION REPEATABLE READ, " +
+				"READ WRITE, DEFERRABLE;",
+		},
+	}
+
+	for _, s := range samples {
+		assert.Equal(t, s.expected, s.opts.startTxQuery())
+	}
+
+	assert.PanicsWithValue(t, `unknown isolation level: "read_committed"`,
+		func() { NewTxOptions().WithIsolation("read_committed") })
+}
+
+func TestTxPreferRepeatableRead(t *testing.T) {
+	ctx := context.Background()
+
+	for _, readOnly := range []bool{true, false} {
+		opts := newTxOpts(PreferRepeatableRead, readOnly, false)
+		err := client.WithTxOptions(opts).Tx(
+			ctx,
+			func(ctx context.Context, tx *Tx) error {
+				var result int64
+				return tx.QuerySingle(ctx, "SELECT 1", &result)
+			},
+		)
+		assert.NoError(t, err, "read only: %v", readOnly)
+	}
+}
+
+func TestTxPreferRepeatableReadFallback(t *testing.T) {
+	ctx := context.Background()
+	opts := NewTxOptions().WithIsolation(PreferRepeatableRead)
+	unsupported := &unsupportedFeatureError{
+		msg: "INSERT is not supported in REPEATABLE READ transactions",
+	}
+
+	// A statement that fails because it is not supported in repeatable read
+	// makes the whole transaction run again with Serializable.
+	var isolations []IsolationLevel
+	err := client.WithTxOptions(opts).Tx(
+		ctx,
+		func(ctx context.Context, tx *Tx) error {
+			isolations = append(isolations, tx.isolation)
+			if tx.isolation == RepeatableRead {
+				return unsupported
+			}
+
+			var result int64
+			return tx.QuerySingle(ctx, "SELECT 1", &result)
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []IsolationLevel{RepeatableRead, Serializable}, isolations)
+
+	// Transactions that do not prefer repeatable read are not run again.
+	attempts := 0
+	err = client.WithTxOptions(opts.WithIsolation(RepeatableRead)).Tx(
+		ctx,
+		func(ctx context.Context, tx *Tx) error {
+			attempts++
+			return unsupported
+		},
+	)
+	assert.EqualError(t, err, "edgedb.UnsupportedFeatureError: "+
+		"INSERT is not supported in REPEATABLE READ transactions")
+	assert.Equal(t, 1, attempts)
+
+	// Other server errors do not change the isolation level.
+	isolations = nil
+	err = client.WithTxOptions(opts).Tx(
+		ctx,
+		func(ctx context.Context, tx *Tx) error {
+			isolations = append(isolations, tx.isolation)
+			return &unsupportedFeatureError{msg: "not supported"}
+		},
+	)
+	assert.EqualError(t, err, "edgedb.UnsupportedFeatureError: not supported")
+	assert.Equal(t, []IsolationLevel{RepeatableRead}, isolations)
+}
+
+func TestIsRepeatableReadUnsupported(t *testing.T) {
+	assert.True(t, isRepeatableReadUnsupported(&unsupportedFeatureError{
+		msg: "INSERT is not supported in REPEATABLE READ transactions",
+	}))
+	assert.True(t, isRepeatableReadUnsupported(fmt.Errorf("wrapped: %w",
+		&transactionError{msg: "not supported in repeatable read"})))
+	assert.False(t, isRepeatableReadUnsupported(
+		&unsupportedFeatureError{msg: "not supported"}))
+	assert.False(t, isRepeatableReadUnsupported(
+		&interfaceError{msg: "not supported in repeatable read"}))
+	assert.False(t, isRepeatableReadUnsupported(
+		errors.New("not supported in repeatable read")))
+}
+
+func TestRetryOptionsWithIsolation(t *testing.T) {
+	options := NewRetryOptions().
+		WithIsolation(RepeatableRead, NewRetryRule().WithAttempts(2)).
+		WithIsolation(Serializable, NewRetryRule().WithAttempts(5))
+	conflict := &transactionSerializationError{msg: "conflict"}
+
+	rule, err := options.ruleForException(conflict, RepeatableRead)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rule.attempts)
+
+	rule, err = options.ruleForException(conflict, Serializable)
+	require.NoError(t, err)
+	assert.Equal(t, 5, rule.attempts)
+
+	// queries outside of transactions use the TxConflict rule
+	rule, err = options.ruleForException(conflict, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, rule.attempts)
+
+	// isolation rules only apply to transaction conflicts
+	rule, err = options.ruleForException(
+		&clientConnectionClosedError{}, RepeatableRead)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rule.attempts)
+
+	rule, err = NewRetryOptions().
+		ruleForException(conflict, RepeatableRead)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rule.attempts)
+
+	assert.PanicsWithValue(t,
+		`unexpected isolation level: "prefer_repeatable_read"`,
+		func() {
+			NewRetryOptions().
+				WithIsolation(PreferRepeatableRead, NewRetryRule())
+		})
+}
+
 func TestWithConfigInTx(t *testing.T) {
 	if protocolVersion.LT(protocolVersion1p0) {
 		t.Skip()
@@ -208,3 +358,356 @@ func TestWithConfigInTx(t *testing.T) {
 	})
 	assert.EqualError(t, err, "rollback")
 }
+
+func TestTxSavepoint(t *testing.T) {
+	ctx := context.Background()
+	err := client.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		e := tx.Execute(ctx, "INSERT TxTest {name := 'Savepoint Keep'};")
+		require.NoError(t, e)
+
+		require.NoError(t, tx.Savepoint(ctx, "sp1"))
+		e = tx.Execute(ctx, "INSERT TxTest {name := 'Savepoint Undo'};")
+		require.NoError(t, e)
+
+		e = tx.Execute(ctx, "SELECT 1 / 0;")
+		require.Error(t, e)
+
+		require.NoError(t, tx.RollbackToSavepoint(ctx, "sp1"))
+		return tx.ReleaseSavepoint(ctx, "sp1")
+	})
+	require.NoError(t, err)
+
+	var names []string
+	err = client.Query(ctx, `
+		SELECT TxTest.name
+		FILTER TxTest.name IN {'Savepoint Keep', 'Savepoint Undo'}`,
+		&names,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Savepoint Keep"}, names)
+}
+
+func TestTxSavepointErrors(t *testing.T) {
+	ctx := context.Background()
+	err := client.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		e := tx.Savepoint(ctx, "bad name")
+		assert.EqualError(t, e, "edgedb.InvalidArgumentError: "+
+			"invalid savepoint name \"bad name\": names must start "+
+			"with a letter or underscore and contain only letters, "+
+			"digits and underscores")
+
+		e = tx.RollbackToSavepoint(ctx, "missing")
+		assert.EqualError(t, e, "edgedb.InterfaceError: "+
+			"cannot roll back to savepoint \"missing\"; it is not declared")
+
+		require.NoError(t, tx.Savepoint(ctx, "sp1"))
+		require.NoError(t, tx.Savepoint(ctx, "sp2"))
+		require.NoError(t, tx.ReleaseSavepoint(ctx, "sp1"))
+
+		e = tx.ReleaseSavepoint(ctx, "sp2")
+		assert.EqualError(t, e, "edgedb.InterfaceError: "+
+			"cannot release savepoint \"sp2\"; it is not declared")
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestTxNested(t *testing.T) {
+	ctx := context.Background()
+	err := client.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		e := tx.Nested(ctx, func(ctx context.Context, tx *Tx) error {
+			return tx.Execute(ctx, "INSERT TxTest {name := 'Nested Keep'};")
+		})
+		require.NoError(t, e)
+
+		e = tx.Nested(ctx, func(ctx context.Context, tx *Tx) error {
+			err := tx.Execute(ctx, "INSERT TxTest {name := 'Nested Undo'};")
+			require.NoError(t, err)
+			return errors.New("user defined error")
+		})
+		assert.EqualError(t, e, "user defined error")
+		assert.Empty(t, tx.savepoints)
+
+		for i := 0; i < 3; i++ {
+			e = tx.Nested(ctx, func(ctx context.Context, tx *Tx) error {
+				return tx.Execute(ctx, "SELECT 1 / 0;")
+			})
+			require.Error(t, e)
+		}
+		assert.Empty(t, tx.savepoints)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	var names []string
+	err = client.Query(ctx, `
+		SELECT TxTest.name
+		FILTER TxTest.name IN {'Nested Keep', 'Nested Undo'}`,
+		&names,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Nested Keep"}, names)
+}
+
+func TestBeginTxCommit(t *testing.T) {
+	ctx := context.Background()
+	tx, err := client.BeginTx(ctx, NewTxOptions())
+	require.NoError(t, err)
+
+	e := tx.Execute(ctx, "INSERT TxTest {name := 'BeginTx Commit'};")
+	require.NoError(t, e)
+	require.NoError(t, tx.Commit(ctx))
+
+	e = tx.Commit(ctx)
+	assert.EqualError(t, e, "edgedb.InterfaceError: "+
+		"cannot commit; the transaction is already committed")
+
+	var count int64
+	err = client.QuerySingle(ctx,
+		"SELECT count(TxTest FILTER .name = 'BeginTx Commit')", &count)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestBeginTxRollback(t *testing.T) {
+	ctx := context.Background()
+	tx, err := client.BeginTx(ctx, NewTxOptions())
+	require.NoError(t, err)
+
+	e := tx.Execute(ctx, "INSERT TxTest {name := 'BeginTx Rollback'};")
+	require.NoError(t, e)
+	require.NoError(t, tx.Rollback(ctx))
+
+	e = tx.Execute(ctx, "SELECT 1;")
+	assert.EqualError(t, e, "edgedb.InterfaceError: "+
+		"cannot Execute; the transaction is already rolled back")
+
+	var count int64
+	err = client.QuerySingle(ctx,
+		"SELECT count(TxTest FILTER .name = 'BeginTx Rollback')", &count)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestBeginTxReadOnly(t *testing.T) {
+	ctx := context.Background()
+	tx, err := client.BeginTx(ctx, NewTxOptions().WithReadOnly(true))
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // nolint:errcheck
+
+	e := tx.Execute(ctx, "INSERT TxTest {name := 'BeginTx ReadOnly'};")
+	var edbErr Error
+	assert.True(t, errors.As(e, &edbErr), e)
+}
+
+func TestCommitInTxBlock(t *testing.T) {
+	ctx := context.Background()
+	err := client.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		e := tx.Commit(ctx)
+		assert.EqualError(t, e, "edgedb.InterfaceError: "+
+			"cannot commit; the transaction is managed by Client.Tx()")
+
+		e = tx.Rollback(ctx)
+		assert.EqualError(t, e, "edgedb.InterfaceError: "+
+			"cannot rollback; the transaction is managed by Client.Tx()")
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(errors.New("error")))
+	assert.False(t, IsRetryable(&interfaceError{msg: "error"}))
+	assert.True(t, IsRetryable(&transactionSerializationError{msg: "error"}))
+	assert.True(t, IsRetryable(fmt.Errorf(
+		"wrapped: %w", &transactionDeadlockError{msg: "error"})))
+}
+
+func TestRetryRuleNextBackoff(t *testing.T) {
+	ctx := context.Background()
+	second := func(int) time.Duration { return time.Second }
+	rule := NewRetryRule().WithBackoff(second)
+
+	backoff, ok := rule.nextBackoff(ctx, 1, time.Now())
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, backoff)
+
+	backoff, ok = rule.WithMaxBackoff(time.Millisecond).
+		nextBackoff(ctx, 1, time.Now())
+	assert.True(t, ok)
+	assert.Equal(t, time.Millisecond, backoff)
+
+	_, ok = rule.WithMaxElapsed(time.Second).
+		nextBackoff(ctx, 1, time.Now().Add(-time.Millisecond))
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, ok = rule.nextBackoff(ctx, 1, time.Now())
+	assert.False(t, ok)
+}
+
+func TestSleepCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := sleep(ctx, time.Minute)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTxRetryStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	hour := func(int) time.Duration { return time.Hour }
+	c := client.WithRetryOptions(
+		NewRetryOptions().WithDefault(NewRetryRule().WithBackoff(hour)))
+
+	start := time.Now()
+	attempts := 0
+	err := c.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		attempts++
+		return &transactionSerializationError{msg: "conflict"}
+	})
+	assert.EqualError(t, err,
+		"edgedb.TransactionSerializationError: conflict")
+	assert.Equal(t, 1, attempts)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestDerivedClientOptionsOnPooledConnection(t *testing.T) {
+	o := opts
+	o.Concurrency = 1
+
+	ctx := context.Background()
+	p, err := CreateClient(ctx, o)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, p.Close()) }()
+
+	// put a connection with the default options in the pool
+	var result int64
+	require.NoError(t, p.QuerySingle(ctx, "SELECT 1", &result))
+
+	readOnly := p.WithTxOptions(NewTxOptions().WithReadOnly(true))
+	err = readOnly.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		return tx.Execute(ctx, "INSERT TxTest {name := 'pooled read only'}")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only")
+
+	once := p.WithRetryOptions(
+		NewRetryOptions().WithDefault(NewRetryRule().WithAttempts(1)))
+	attempts := 0
+	err = once.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		attempts++
+		return &transactionSerializationError{msg: "conflict"}
+	})
+	assert.EqualError(t, err,
+		"edgedb.TransactionSerializationError: conflict")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestTxRetryPredicate(t *testing.T) {
+	ctx := context.Background()
+
+	noWait := func(int) time.Duration { return 0 }
+	type retry struct {
+		attempt int
+		err     string
+	}
+	var retries []retry
+
+	isAvailabilityError := func(err Error) bool {
+		return err.Category(AvailabilityError)
+	}
+	c := client.WithRetryOptions(NewRetryOptions().
+		WithPredicate(
+			isAvailabilityError,
+			NewRetryRule().WithAttempts(3).WithBackoff(noWait),
+		).
+		OnRetry(func(attempt int, err error, wait time.Duration) {
+			retries = append(retries, retry{attempt, err.Error()})
+			assert.Equal(t, time.Duration(0), wait)
+		}))
+
+	attempts := 0
+	err := c.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		attempts++
+		if attempts < 3 {
+			return &availabilityError{msg: "failover"}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []retry{
+		{1, "edgedb.AvailabilityError: failover"},
+		{2, "edgedb.AvailabilityError: failover"},
+	}, retries)
+
+	// Errors that are not matched by a predicate are not retried.
+	attempts = 0
+	err = c.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		attempts++
+		return &queryError{msg: "bad query"}
+	})
+	assert.EqualError(t, err, "edgedb.QueryError: bad query")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestTxRetryPredicateOnCommit(t *testing.T) {
+	ctx := context.Background()
+
+	noWait := func(int) time.Duration { return 0 }
+	isInterfaceError := func(err Error) bool {
+		return err.Category(InterfaceError)
+	}
+
+	// Rolling back inside the action makes the commit fail
+	// with an error that is only retryable because of the predicate.
+	run := func(c *Client) (int, error) {
+		attempts := 0
+		err := c.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+			attempts++
+			if attempts == 1 {
+				return tx.rollback(ctx)
+			}
+			return nil
+		})
+		return attempts, err
+	}
+
+	attempts, err := run(client.WithRetryOptions(NewRetryOptions().
+		WithPredicate(
+			isInterfaceError,
+			NewRetryRule().WithAttempts(2).WithBackoff(noWait),
+		)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	attempts, err = run(client)
+	assert.EqualError(t, err, "edgedb.InterfaceError: "+
+		"cannot commit; the transaction is already rolled back")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryOptionsWithPredicateCopies(t *testing.T) {
+	never := func(Error) bool { return false }
+	always := func(Error) bool { return true }
+
+	base := NewRetryOptions().WithPredicate(never, NewRetryRule())
+	a := base.WithPredicate(always, NewRetryRule().WithAttempts(5))
+	b := base.WithPredicate(never, NewRetryRule())
+
+	err := &queryError{msg: "error"}
+	assert.True(t, a.shouldRetry(err))
+	assert.False(t, b.shouldRetry(err))
+
+	rule, e := a.ruleForException(err, Serializable)
+	require.NoError(t, e)
+	assert.Equal(t, 5, rule.attempts)
+}
@@ -5487,6 +5487,70 @@ func (m *CustomDecimal) UnmarshalEdgeDBDecimal(data []byte) error {
 	return nil
 }
 
+func TestSendAndReceiveDecimal(t *testing.T) {
+	ctx := context.Background()
+
+	query := `
+		WITH
+			d := <decimal>$0,
+			s := <str>$1
+		SELECT (
+			encoded := <str>d,
+			decoded := <decimal>s,
+			round_trip := d,
+			is_equal := <decimal>s = d,
+		)
+	`
+
+	type Result struct {
+		Encoded   string        `edgedb:"encoded"`
+		Decoded   types.Decimal `edgedb:"decoded"`
+		RoundTrip types.Decimal `edgedb:"round_trip"`
+		IsEqual   bool          `edgedb:"is_equal"`
+	}
+
+	samples := []string{
+		"0",
+		"1",
+		"-1",
+		"0.1",
+		"-0.05",
+		"12.340",
+		"10000",
+		"0.0001",
+		"123456789.987654321",
+		"-15000.6250000",
+		"11001200000031231238172638172637981268371628312300000000.5",
+	}
+
+	for _, s := range samples {
+		t.Run(s, func(t *testing.T) {
+			d, err := types.ParseDecimal(s)
+			require.NoError(t, err)
+
+			var result Result
+			err = client.QuerySingle(ctx, query, &result, d, s)
+			require.NoError(t, err)
+
+			assert.True(t, result.IsEqual, "equality check failed")
+			assert.Equal(t, s, result.Encoded, "encoding failed")
+			assert.Equal(t, s, result.Decoded.String(), "decoding failed")
+			assert.Equal(t, s, result.RoundTrip.String())
+		})
+	}
+
+	var optional struct {
+		Val types.OptionalDecimal `edgedb:"val"`
+	}
+	err := client.QuerySingle(ctx, `
+		SELECT { val := <OPTIONAL decimal>$0 }`,
+		&optional,
+		types.OptionalDecimal{},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, types.OptionalDecimal{}, optional.Val)
+}
+
 func TestReceiveDecimalUnmarshaler(t *testing.T) {
 	ctx := context.Background()
 	var result struct {
@@ -7869,6 +7933,33 @@ func TestEmptyMultiRange(t *testing.T) {
 	assert.Equal(t, emptyMultiRange, result)
 }
 
+func TestOptionalMultiRange(t *testing.T) {
+	if !serverHasMultiRange(t) {
+		t.Skip("server lacks std::MultiRange support")
+	}
+
+	ctx := context.Background()
+
+	var result struct {
+		Missing types.Opt[types.MultiRangeInt32] `edgedb:"missing"`
+		Present types.Opt[types.MultiRangeInt32] `edgedb:"present"`
+	}
+
+	query := `SELECT {
+		missing := <multirange<int32>>{},
+		present := multirange([range(1, 5)]),
+	}`
+	err := client.QuerySingle(ctx, query, &result)
+	require.NoError(t, err)
+	assert.Equal(t, types.Opt[types.MultiRangeInt32]{}, result.Missing)
+	assert.Equal(t, types.NewOpt(types.MultiRangeInt32{types.NewRangeInt32(
+		types.NewOptionalInt32(1),
+		types.NewOptionalInt32(5),
+		true,
+		false,
+	)}), result.Present)
+}
+
 func TestCustomSequenceTypeHandling(t *testing.T) {
 	ddl := `
 		CREATE SCALAR TYPE SampleSequence extending std::sequence;
@@ -7893,3 +7984,49 @@ func TestCustomSequenceTypeHandling(t *testing.T) {
 		assert.True(t, isSet)
 	})
 }
+
+// The server sends ext::postgis::box2d and ext::postgis::box3d
+// as the well-known binary of the equivalent geometry.
+func TestReceivePostGISBoxes(t *testing.T) {
+	if protocolVersion.LT(protocolVersion2p0) {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	c := createTestBranch(t, "postgis")
+	if err := c.Execute(ctx, "CREATE EXTENSION postgis;"); err != nil {
+		t.Skipf("postgis extension is not available: %v", err)
+	}
+
+	polygon := "POLYGON((0 0,0 1,1 1,1 0,0 0))"
+	var result struct {
+		Box2D           types.Box2D    `edgedb:"box2d"`
+		Box3D           types.Box3D    `edgedb:"box3d"`
+		Geometry        types.Geometry `edgedb:"geometry"`
+		Box3DAsGeometry types.Geometry `edgedb:"box3d_as_geometry"`
+	}
+	err := c.QuerySingle(ctx, `
+		WITH g := <ext::postgis::geometry><str>$0
+		SELECT {
+			box2d := <ext::postgis::box2d>g,
+			box3d := <ext::postgis::box3d>g,
+			geometry := <ext::postgis::geometry><ext::postgis::box2d>g,
+			box3d_as_geometry :=
+				<ext::postgis::geometry><ext::postgis::box3d>g,
+		}`,
+		&result,
+		polygon,
+	)
+	require.NoError(t, err)
+
+	expected, err := types.ParseGeometry(polygon)
+	require.NoError(t, err)
+	shape, err := types.NewGeometryFromWKB(result.Box2D.WKB()).Shape()
+	require.NoError(t, err)
+	expectedShape, err := expected.Shape()
+	require.NoError(t, err)
+	assert.Equal(t, expectedShape, shape)
+
+	assert.Equal(t, result.Geometry.WKB(), result.Box2D.WKB())
+	assert.Equal(t, result.Box3DAsGeometry.WKB(), result.Box3D.WKB())
+}
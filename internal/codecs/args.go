@@ -18,11 +18,13 @@ package codecs
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/edgedb/edgedb-go/internal"
 	"github.com/edgedb/edgedb-go/internal/buff"
 	"github.com/edgedb/edgedb-go/internal/descriptor"
 	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/edgedb/edgedb-go/internal/introspect"
 )
 
 func buildArgEncoder(
@@ -109,7 +111,7 @@ func (c *argsEncoder) Encode(
 	var err error
 	for i, field := range c.fields {
 		w.PushUint32(0) // reserved
-		err = field.encoder.Encode(w, in[i], path.AddIndex(i), field.required)
+		err = field.encode(w, in[i], path.AddIndex(i))
 		if err != nil {
 			return err
 		}
@@ -143,26 +145,18 @@ func (c *kwargsEncoder) Encode(
 		)
 	}
 
-	in, ok := args[0].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf(
-			"expected %v to be map[string]interface{} got %T", path, args[0],
-		)
+	in, err := c.namedArgs(args[0], path)
+	if err != nil {
+		return err
 	}
 
 	elmCount := len(c.fields)
 	w.BeginBytes()
 	w.PushUint32(uint32(elmCount))
 
-	var err error
 	for _, field := range c.fields {
 		w.PushUint32(0) // reserved
-		err = field.encoder.Encode(
-			w,
-			in[field.name],
-			path.AddField(field.name),
-			field.required,
-		)
+		err = field.encode(w, in[field.name], path.AddField(field.name))
 
 		if err != nil {
 			return err
@@ -172,3 +166,94 @@ func (c *kwargsEncoder) Encode(
 	w.EndBytes()
 	return nil
 }
+
+// namedArgs returns the arguments in val by name. val can be
+// a map[string]interface{} or a struct with a field for every argument.
+// Struct fields that do not match an argument are an error.
+func (c *kwargsEncoder) namedArgs(
+	val interface{},
+	path Path,
+) (map[string]interface{}, error) {
+	if in, ok := val.(map[string]interface{}); ok {
+		return in, nil
+	}
+
+	v := reflect.ValueOf(val)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(
+			"expected %v to be map[string]interface{} or a struct got %T",
+			path, val,
+		)
+	}
+
+	typ := v.Type()
+	in := make(map[string]interface{}, len(c.fields))
+	used := make(map[string]bool, len(c.fields))
+	for _, field := range c.fields {
+		sf, ok := introspect.StructField(typ, field.name)
+		if !ok {
+			return nil, fmt.Errorf(
+				"expected %v to have a field for the %q argument",
+				typ, field.name,
+			)
+		}
+
+		if !sf.IsExported() {
+			return nil, fmt.Errorf(
+				"expected %v field %v for the %q argument to be exported",
+				typ, sf.Name, field.name,
+			)
+		}
+
+		fv, err := v.FieldByIndexErr(sf.Index)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot read %v field %v for the %q argument: %w",
+				typ, sf.Name, field.name, err,
+			)
+		}
+
+		in[field.name] = fv.Interface()
+		used[fmt.Sprint(sf.Index)] = true
+	}
+
+	if err := checkUnusedFields(typ, nil, used); err != nil {
+		return nil, err
+	}
+
+	return in, nil
+}
+
+// checkUnusedFields returns an error if typ has a field
+// that is not in used. Fields of structs embedded with the $inline tag
+// are checked too, other embedded structs are ignored.
+func checkUnusedFields(
+	typ reflect.Type,
+	index []int,
+	used map[string]bool,
+) error {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+		switch {
+		case used[fmt.Sprint(fieldIndex)]:
+		case field.Tag.Get("edgedb") == "$inline":
+			err := checkUnusedFields(field.Type, fieldIndex, used)
+			if err != nil {
+				return err
+			}
+		case field.Anonymous:
+		default:
+			return fmt.Errorf(
+				"expected %v field %v to match a query argument",
+				typ, field.Name,
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"testing"
+
+	"github.com/edgedb/edgedb-go/internal"
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func namedArgsEncoder(t *testing.T) Encoder {
+	str := descriptor.V2{Type: descriptor.Scalar, ID: StrID}
+	desc := &descriptor.V2{
+		Type: descriptor.Object,
+		ID:   types.UUID{1},
+		Fields: []*descriptor.FieldV2{
+			{Name: "name", Desc: str, Required: true},
+			{Name: "nickname", Desc: str},
+			{Name: "email", Desc: str},
+		},
+	}
+
+	encoder, err := BuildEncoderV2(desc, internal.ProtocolVersion{Major: 2})
+	require.NoError(t, err)
+	return encoder
+}
+
+func TestNamedArgsStruct(t *testing.T) {
+	type Contact struct {
+		Email *string `edgedb:"email"`
+	}
+
+	type Params struct {
+		Name     string            `edgedb:"name"`
+		Nickname types.Opt[string] `edgedb:"nickname"`
+		Contact  `edgedb:"$inline"`
+	}
+
+	expected := []byte{
+		0, 0, 0, 3, // element count
+		0, 0, 0, 0, // reserved
+		0, 0, 0, 1, // data length
+		'a',
+		0, 0, 0, 0, // reserved
+		0, 0, 0, 1, // data length
+		'b',
+		0, 0, 0, 0, // reserved
+		0xff, 0xff, 0xff, 0xff, // missing
+	}
+
+	encoder := namedArgsEncoder(t)
+	params := Params{Name: "a", Nickname: types.NewOpt("b")}
+	data := encodeTestValue(t, encoder, []interface{}{params})
+	assert.Equal(t, expected, data)
+
+	data = encodeTestValue(t, encoder, []interface{}{&params})
+	assert.Equal(t, expected, data)
+
+	data = encodeTestValue(t, encoder, []interface{}{map[string]interface{}{
+		"name":     "a",
+		"nickname": "b",
+		"email":    types.OptionalStr{},
+	}})
+	assert.Equal(t, expected, data)
+}
+
+func TestNamedArgsStructErrors(t *testing.T) {
+	encoder := namedArgsEncoder(t)
+	w := buff.NewWriter(nil)
+	w.BeginMessage(0)
+
+	type Params struct {
+		Name     string `edgedb:"name"`
+		Nickname string `edgedb:"nick_name"`
+	}
+
+	err := encoder.Encode(w, []interface{}{Params{}}, "args", true)
+	assert.EqualError(t, err, "expected codecs.Params "+
+		`to have a field for the "nickname" argument`)
+
+	err = encoder.Encode(w, []interface{}{"name"}, "args", true)
+	assert.EqualError(t, err, "expected args to be "+
+		"map[string]interface{} or a struct got string")
+
+	type Extra struct {
+		Name     string `edgedb:"name"`
+		Nickname string `edgedb:"nickname"`
+		Email    string `edgedb:"email"`
+		Phone    string `edgedb:"phone"`
+	}
+
+	err = encoder.Encode(w, []interface{}{Extra{}}, "args", true)
+	assert.EqualError(t, err,
+		"expected codecs.Extra field Phone to match a query argument")
+
+	type Contact struct {
+		Email string `edgedb:"email"`
+		Phone string `edgedb:"phone"`
+	}
+
+	type Inline struct {
+		Name     string `edgedb:"name"`
+		Nickname string `edgedb:"nickname"`
+		Contact  `edgedb:"$inline"`
+	}
+
+	err = encoder.Encode(w, []interface{}{Inline{}}, "args", true)
+	assert.EqualError(t, err,
+		"expected codecs.Contact field Phone to match a query argument")
+
+	type Unexported struct {
+		Name     string `edgedb:"name"`
+		Nickname string `edgedb:"nickname"`
+		email    string `edgedb:"email"` // nolint:unused
+	}
+
+	err = encoder.Encode(w, []interface{}{Unexported{}}, "args", true)
+	assert.EqualError(t, err, "expected codecs.Unexported field email "+
+		`for the "email" argument to be exported`)
+}
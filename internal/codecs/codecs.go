@@ -170,10 +170,16 @@ func BuildScalarEncoder(desc descriptor.Descriptor) (Encoder, error) {
 		desc = GetScalarDescriptor(desc)
 	}
 
-	if desc.ID == DecimalID {
-		return &decimalEncoder{}, nil
+	encoder, err := buildBuiltinScalarEncoder(desc)
+	byType := registeredCodecs(stdScalarNames[desc.ID])
+	if byType != nil {
+		return &registeredEncoder{desc.ID, byType, encoder}, nil
 	}
 
+	return encoder, err
+}
+
+func buildBuiltinScalarEncoder(desc descriptor.Descriptor) (Encoder, error) {
 	if desc.Type == descriptor.Enum {
 		return &StrCodec{desc.ID}, nil
 	}
@@ -196,9 +202,7 @@ func BuildScalarEncoder(desc descriptor.Descriptor) (Encoder, error) {
 	case Float64ID:
 		return &Float64Codec{}, nil
 	case DecimalID:
-		return nil, errors.New("decimal codec not implemented. " +
-			"Consider implementing your own edgedb.DecimalMarshaler " +
-			"and edgedb.DecimalUnmarshaler.")
+		return &DecimalCodec{}, nil
 	case BoolID:
 		return &BoolCodec{}, nil
 	case DateTimeID:
@@ -229,18 +233,32 @@ func BuildScalarEncoder(desc descriptor.Descriptor) (Encoder, error) {
 
 // BuildScalarEncoderV2 builds a scalar encoder.
 func BuildScalarEncoderV2(desc *descriptor.V2) (Encoder, error) {
-	if desc.Type == descriptor.Scalar {
-		desc = GetScalarDescriptorV2(desc)
+	encoder, err := buildBuiltinScalarEncoderV2(desc)
+	byType := registeredCodecs(scalarNamesV2(desc)...)
+	if byType != nil {
+		return &registeredEncoder{desc.ID, byType, encoder}, nil
 	}
 
-	if desc.ID == DecimalID {
-		return &decimalEncoder{}, nil
+	return encoder, err
+}
+
+func buildBuiltinScalarEncoderV2(desc *descriptor.V2) (Encoder, error) {
+	if desc.Type == descriptor.Scalar {
+		desc = GetScalarDescriptorV2(desc)
 	}
 
 	if desc.Type == descriptor.Enum {
 		return &StrCodec{desc.ID}, nil
 	}
 
+	if encoder, ok := buildPGVectorEncoder(desc); ok {
+		return encoder, nil
+	}
+
+	if encoder, ok := buildPostGISEncoder(desc); ok {
+		return encoder, nil
+	}
+
 	switch desc.ID {
 	case UUIDID:
 		return &UUIDCodec{}, nil
@@ -259,9 +277,7 @@ func BuildScalarEncoderV2(desc *descriptor.V2) (Encoder, error) {
 	case Float64ID:
 		return &Float64Codec{}, nil
 	case DecimalID:
-		return nil, errors.New("decimal codec not implemented. " +
-			"Consider implementing your own edgedb.DecimalMarshaler " +
-			"and edgedb.DecimalUnmarshaler.")
+		return &DecimalCodec{}, nil
 	case BoolID:
 		return &BoolCodec{}, nil
 	case DateTimeID:
@@ -300,6 +316,14 @@ func BuildDecoder(
 		return noOpDecoder{}, nil
 	}
 
+	if decoder, ok, err := buildPointerDecoder(desc, typ, path); ok {
+		return decoder, err
+	}
+
+	if decoder, ok, err := buildOptDecoder(desc, typ, path); ok {
+		return decoder, err
+	}
+
 	switch desc.Type {
 	case descriptor.Set:
 		return buildSetDecoder(desc, typ, path)
@@ -332,6 +356,18 @@ func BuildDecoderV2(
 		return noOpDecoder{}, nil
 	}
 
+	if decoder, ok, err := buildPointerDecoderV2(desc, typ, path); ok {
+		return decoder, err
+	}
+
+	if decoder, ok, err := buildOptDecoderV2(desc, typ, path); ok {
+		return decoder, err
+	}
+
+	if isDynamic(desc, typ) {
+		return buildDynamicDecoderV2(desc, typ, path)
+	}
+
 	switch desc.Type {
 	case descriptor.Set:
 		return buildSetDecoderV2(desc, typ, path)
@@ -365,6 +401,12 @@ func buildScalarDecoder(
 		desc = GetScalarDescriptor(desc)
 	}
 
+	registered, ok := buildRegisteredDecoder(
+		desc.ID, typ, stdScalarNames[desc.ID])
+	if ok {
+		return registered, nil
+	}
+
 	decoder, ok, err := buildUnmarshaler(desc, typ)
 	if err != nil {
 		return decoder, err
@@ -461,9 +503,14 @@ func buildScalarDecoder(
 			expectedType = "float64 or edgedb.OptionalFloat64"
 		}
 	case DecimalID:
-		return nil, errors.New("decimal codec not implemented. " +
-			"Consider implementing your own edgedb.DecimalMarshaler " +
-			"and edgedb.DecimalUnmarshaler.")
+		switch typ {
+		case decimalType:
+			return &DecimalCodec{}, nil
+		case optionalDecimalType:
+			return &optionalDecimalDecoder{}, nil
+		default:
+			expectedType = "edgedb.Decimal or edgedb.OptionalDecimal"
+		}
 	case BoolID:
 		switch typ {
 		case boolType:
@@ -592,6 +639,12 @@ func buildScalarDecoderV2(
 	typ reflect.Type,
 	path Path,
 ) (Decoder, error) {
+	registered, ok := buildRegisteredDecoder(
+		desc.ID, typ, scalarNamesV2(desc)...)
+	if ok {
+		return registered, nil
+	}
+
 	if desc.Type == descriptor.Scalar {
 		desc = GetScalarDescriptorV2(desc)
 	}
@@ -604,6 +657,22 @@ func buildScalarDecoderV2(
 		return decoder, nil
 	}
 
+	decoder, ok, err = buildPGVectorDecoder(desc, typ, path)
+	if err != nil {
+		return decoder, err
+	}
+	if ok {
+		return decoder, nil
+	}
+
+	decoder, ok, err = buildPostGISDecoder(desc, typ, path)
+	if err != nil {
+		return decoder, err
+	}
+	if ok {
+		return decoder, nil
+	}
+
 	var expectedType string
 
 	if desc.Type == descriptor.Enum {
@@ -692,9 +761,14 @@ func buildScalarDecoderV2(
 			expectedType = "float64 or edgedb.OptionalFloat64"
 		}
 	case DecimalID:
-		return nil, errors.New("decimal codec not implemented. " +
-			"Consider implementing your own edgedb.DecimalMarshaler " +
-			"and edgedb.DecimalUnmarshaler.")
+		switch typ {
+		case decimalType:
+			return &DecimalCodec{}, nil
+		case optionalDecimalType:
+			return &optionalDecimalDecoder{}, nil
+		default:
+			expectedType = "edgedb.Decimal or edgedb.OptionalDecimal"
+		}
 	case BoolID:
 		switch typ {
 		case boolType:
@@ -69,34 +69,49 @@ var (
 	// MemoryID is the cfg::memory type descriptor ID
 	MemoryID = types.UUID{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0x30}
 
-	int16Type                 = reflect.TypeOf(int16(0))
-	int32Type                 = reflect.TypeOf(int32(0))
-	int64Type                 = reflect.TypeOf(int64(0))
-	float32Type               = reflect.TypeOf(float32(0))
-	float64Type               = reflect.TypeOf(float64(0))
-	optionalInt16Type         = reflect.TypeOf(types.OptionalInt16{})
-	optionalInt32Type         = reflect.TypeOf(types.OptionalInt32{})
-	optionalInt64Type         = reflect.TypeOf(types.OptionalInt64{})
-	optionalFloat32Type       = reflect.TypeOf(types.OptionalFloat32{})
-	optionalFloat64Type       = reflect.TypeOf(types.OptionalFloat64{})
-	strType                   = reflect.TypeOf("")
-	optionalStrType           = reflect.TypeOf(types.OptionalStr{})
-	boolType                  = reflect.TypeOf(false)
-	optionalBoolType          = reflect.TypeOf(types.OptionalBool{})
-	uuidType                  = reflect.TypeOf(UUIDID)
-	optionalUUIDType          = reflect.TypeOf(types.OptionalUUID{})
-	bytesType                 = reflect.TypeOf([]byte{})
-	optionalBytesType         = reflect.TypeOf(types.OptionalBytes{})
-	dateTimeType              = reflect.TypeOf(time.Time{})
-	localDateTimeType         = reflect.TypeOf(types.LocalDateTime{})
-	localDateType             = reflect.TypeOf(types.LocalDate{})
-	localTimeType             = reflect.TypeOf(types.LocalTime{})
-	durationType              = reflect.TypeOf(types.Duration(0))
-	relativeDurationType      = reflect.TypeOf(types.RelativeDuration{})
-	dateDurationType          = reflect.TypeOf(types.DateDuration{})
-	bigIntType                = reflect.TypeOf(&big.Int{})
-	memoryType                = reflect.TypeOf(types.Memory(0))
-	optionalBigIntType        = reflect.TypeOf(types.OptionalBigInt{})
+	int16Type                = reflect.TypeOf(int16(0))
+	int32Type                = reflect.TypeOf(int32(0))
+	int64Type                = reflect.TypeOf(int64(0))
+	float32Type              = reflect.TypeOf(float32(0))
+	float64Type              = reflect.TypeOf(float64(0))
+	optionalInt16Type        = reflect.TypeOf(types.OptionalInt16{})
+	optionalInt32Type        = reflect.TypeOf(types.OptionalInt32{})
+	optionalInt64Type        = reflect.TypeOf(types.OptionalInt64{})
+	optionalFloat32Type      = reflect.TypeOf(types.OptionalFloat32{})
+	optionalFloat64Type      = reflect.TypeOf(types.OptionalFloat64{})
+	strType                  = reflect.TypeOf("")
+	optionalStrType          = reflect.TypeOf(types.OptionalStr{})
+	boolType                 = reflect.TypeOf(false)
+	optionalBoolType         = reflect.TypeOf(types.OptionalBool{})
+	uuidType                 = reflect.TypeOf(UUIDID)
+	optionalUUIDType         = reflect.TypeOf(types.OptionalUUID{})
+	bytesType                = reflect.TypeOf([]byte{})
+	optionalBytesType        = reflect.TypeOf(types.OptionalBytes{})
+	dateTimeType             = reflect.TypeOf(time.Time{})
+	localDateTimeType        = reflect.TypeOf(types.LocalDateTime{})
+	localDateType            = reflect.TypeOf(types.LocalDate{})
+	localTimeType            = reflect.TypeOf(types.LocalTime{})
+	durationType             = reflect.TypeOf(types.Duration(0))
+	relativeDurationType     = reflect.TypeOf(types.RelativeDuration{})
+	dateDurationType         = reflect.TypeOf(types.DateDuration{})
+	bigIntType               = reflect.TypeOf(&big.Int{})
+	memoryType               = reflect.TypeOf(types.Memory(0))
+	optionalBigIntType       = reflect.TypeOf(types.OptionalBigInt{})
+	decimalType              = reflect.TypeOf(types.Decimal{})
+	optionalDecimalType      = reflect.TypeOf(types.OptionalDecimal{})
+	float32SliceType         = reflect.TypeOf([]float32{})
+	optionalVectorType       = reflect.TypeOf(types.OptionalVector{})
+	sparseVectorType         = reflect.TypeOf(types.SparseVector{})
+	optionalSparseVectorType = reflect.TypeOf(
+		types.OptionalSparseVector{})
+	geometryType              = reflect.TypeOf(types.Geometry{})
+	optionalGeometryType      = reflect.TypeOf(types.OptionalGeometry{})
+	geographyType             = reflect.TypeOf(types.Geography{})
+	optionalGeographyType     = reflect.TypeOf(types.OptionalGeography{})
+	box2DType                 = reflect.TypeOf(types.Box2D{})
+	optionalBox2DType         = reflect.TypeOf(types.OptionalBox2D{})
+	box3DType                 = reflect.TypeOf(types.Box3D{})
+	optionalBox3DType         = reflect.TypeOf(types.OptionalBox3D{})
 	optionalDateTimeType      = reflect.TypeOf(types.OptionalDateTime{})
 	optionalLocalDateTimeType = reflect.TypeOf(
 		types.OptionalLocalDateTime{})
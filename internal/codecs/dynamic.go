@@ -0,0 +1,460 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+)
+
+var (
+	interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+	mapType       = reflect.TypeOf(map[string]interface{}{})
+	objectType    = reflect.TypeOf(types.Object{})
+
+	// dynamicScalarTypes are the go types
+	// that scalars are decoded into when decoding dynamically.
+	dynamicScalarTypes = map[types.UUID]reflect.Type{
+		UUIDID:             uuidType,
+		StrID:              strType,
+		BytesID:            bytesType,
+		Int16ID:            int16Type,
+		Int32ID:            int32Type,
+		Int64ID:            int64Type,
+		Float32ID:          float32Type,
+		Float64ID:          float64Type,
+		DecimalID:          decimalType,
+		BoolID:             boolType,
+		DateTimeID:         dateTimeType,
+		LocalDTID:          localDateTimeType,
+		LocalDateID:        localDateType,
+		LocalTimeID:        localTimeType,
+		DurationID:         durationType,
+		JSONID:             interfaceType,
+		BigIntID:           bigIntType,
+		RelativeDurationID: relativeDurationType,
+		DateDurationID:     dateDurationType,
+		MemoryID:           memoryType,
+	}
+
+	// dynamicNamedScalarTypes are the go types that extension scalars
+	// are decoded into when decoding dynamically. They are keyed by name
+	// because extension type ids are not fixed.
+	dynamicNamedScalarTypes = map[string]reflect.Type{
+		VectorName:       float32SliceType,
+		HalfVectorName:   float32SliceType,
+		SparseVectorName: sparseVectorType,
+		GeometryName:     geometryType,
+		GeographyName:    geographyType,
+		Box2DName:        box2DType,
+		Box3DName:        box3DType,
+	}
+
+	// dynamicRangeTypes are the go types that ranges are decoded into
+	// when decoding dynamically. They are keyed by the element type id.
+	dynamicRangeTypes = map[types.UUID]reflect.Type{
+		Int32ID:     rangeInt32Type,
+		Int64ID:     rangeInt64Type,
+		Float32ID:   rangeFloat32Type,
+		Float64ID:   rangeFloat64Type,
+		DateTimeID:  rangeDateTimeType,
+		LocalDTID:   rangeLocalDateTimeType,
+		LocalDateID: rangeLocalDateType,
+	}
+)
+
+// isDynamic returns true if values described by desc
+// should be decoded dynamically into typ.
+func isDynamic(desc *descriptor.V2, typ reflect.Type) bool {
+	switch typ {
+	case interfaceType:
+		return true
+	case mapType, objectType:
+		return desc.Type == descriptor.Object || isNamedTupleV2(desc)
+	default:
+		return false
+	}
+}
+
+// isNamedTupleV2 returns true if desc is a named tuple.
+// descriptor.PopV2 gives named tuples the Tuple type,
+// but unlike tuple fields named tuple fields are not named by index.
+func isNamedTupleV2(desc *descriptor.V2) bool {
+	switch desc.Type {
+	case descriptor.NamedTuple:
+		return true
+	case descriptor.Tuple:
+		return len(desc.Fields) > 0 && desc.Fields[0].Name != "0"
+	default:
+		return false
+	}
+}
+
+// buildDynamicDecoderV2 builds a decoder that decodes values without
+// knowing their shape ahead of time. typ must be interface{},
+// map[string]interface{} or edgedb.Object.
+//
+// Scalars are decoded into the same types that a typed decoder would
+// require. Sets, arrays and tuples are decoded into []interface{}.
+// Objects and named tuples are decoded into map[string]interface{} if typ
+// is a map, otherwise they are decoded into edgedb.Object.
+func buildDynamicDecoderV2(
+	desc *descriptor.V2,
+	typ reflect.Type,
+	path Path,
+) (Decoder, error) {
+	value, err := buildDynamicValueV2(desc, typ == mapType, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamicDecoder{id: desc.ID, typ: typ, value: value}, nil
+}
+
+type dynamicDecoder struct {
+	id    types.UUID
+	typ   reflect.Type
+	value dynamicValue
+}
+
+func (c *dynamicDecoder) DescriptorID() types.UUID { return c.id }
+
+func (c *dynamicDecoder) Decode(r *buff.Reader, out unsafe.Pointer) error {
+	val, err := c.value.decode(r)
+	if err != nil {
+		return err
+	}
+
+	switch c.typ {
+	case mapType:
+		*(*map[string]interface{})(out) = val.(map[string]interface{})
+	case objectType:
+		*(*types.Object)(out) = val.(types.Object)
+	default:
+		*(*interface{})(out) = val
+	}
+
+	return nil
+}
+
+func (c *dynamicDecoder) DecodeMissing(out unsafe.Pointer) {
+	reflect.NewAt(c.typ, out).Elem().Set(reflect.Zero(c.typ))
+}
+
+// dynamicValue decodes a value into its default go type.
+type dynamicValue interface {
+	decode(r *buff.Reader) (interface{}, error)
+}
+
+func buildDynamicValueV2(
+	desc *descriptor.V2,
+	asMaps bool,
+	path Path,
+) (dynamicValue, error) {
+	switch desc.Type {
+	case descriptor.Set, descriptor.Array:
+		child, err := buildDynamicValueV2(&desc.Fields[0].Desc, asMaps, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return &dynamicSlice{
+			child: child,
+			isSetOfArrays: desc.Type == descriptor.Set &&
+				desc.Fields[0].Desc.Type == descriptor.Array,
+		}, nil
+	case descriptor.Object, descriptor.NamedTuple:
+		return buildDynamicObjectV2(desc, asMaps, path)
+	case descriptor.Tuple:
+		if isNamedTupleV2(desc) {
+			return buildDynamicObjectV2(desc, asMaps, path)
+		}
+
+		elements := make([]dynamicValue, len(desc.Fields))
+		for i, field := range desc.Fields {
+			child, err := buildDynamicValueV2(
+				&field.Desc,
+				asMaps,
+				path.AddIndex(i),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			elements[i] = child
+		}
+
+		return &dynamicTuple{elements}, nil
+	case descriptor.BaseScalar, descriptor.Scalar, descriptor.Enum:
+		scalar := desc
+		if scalar.Type == descriptor.Scalar {
+			scalar = GetScalarDescriptorV2(scalar)
+		}
+
+		typ := strType
+		if scalar.Type != descriptor.Enum {
+			var ok bool
+			typ, ok = dynamicScalarTypes[scalar.ID]
+			if !ok {
+				typ, ok = dynamicNamedScalarTypes[scalar.Name]
+			}
+			if !ok {
+				return nil, fmt.Errorf(
+					"cannot decode %v dynamically: "+
+						"unsupported scalar type id %v", path, scalar.ID)
+			}
+		}
+
+		return buildDynamicScalarV2(desc, typ, path, buildScalarDecoderV2)
+	case descriptor.Range:
+		typ, err := dynamicRangeType(desc, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildDynamicScalarV2(desc, typ, path, buildRangeDecoderV2)
+	case descriptor.MultiRange:
+		typ, err := dynamicRangeType(&desc.Fields[0].Desc, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildDynamicScalarV2(
+			desc,
+			reflect.SliceOf(typ),
+			path,
+			buildMultiRangeDecoderV2,
+		)
+	default:
+		return nil, fmt.Errorf(
+			"building decoder: unknown descriptor type 0x%x",
+			desc.Type)
+	}
+}
+
+func dynamicRangeType(desc *descriptor.V2, path Path) (reflect.Type, error) {
+	elm := GetScalarDescriptorV2(&desc.Fields[0].Desc)
+	typ, ok := dynamicRangeTypes[elm.ID]
+	if !ok {
+		return nil, fmt.Errorf(
+			"cannot decode %v dynamically: "+
+				"unsupported range element type id %v", path, elm.ID)
+	}
+
+	return typ, nil
+}
+
+func buildDynamicScalarV2(
+	desc *descriptor.V2,
+	typ reflect.Type,
+	path Path,
+	build func(*descriptor.V2, reflect.Type, Path) (Decoder, error),
+) (dynamicValue, error) {
+	decoder, err := build(desc, typ, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamicScalar{typ: typ, decoder: decoder}, nil
+}
+
+// dynamicScalar decodes a value using a typed decoder.
+type dynamicScalar struct {
+	typ     reflect.Type
+	decoder Decoder
+}
+
+func (c *dynamicScalar) decode(r *buff.Reader) (interface{}, error) {
+	val := reflect.New(c.typ)
+	if err := c.decoder.Decode(r, val.UnsafePointer()); err != nil {
+		return nil, err
+	}
+
+	return val.Elem().Interface(), nil
+}
+
+// dynamicSlice decodes sets and arrays into []interface{}.
+type dynamicSlice struct {
+	child         dynamicValue
+	isSetOfArrays bool
+}
+
+func (c *dynamicSlice) decode(r *buff.Reader) (interface{}, error) {
+	// number of dimensions, either 0 or 1
+	if r.PopUint32() == 0 {
+		r.Discard(8) // skip 2 reserved fields
+		return []interface{}{}, nil
+	}
+
+	r.Discard(8) // reserved
+
+	upper := int32(r.PopUint32())
+	lower := int32(r.PopUint32())
+	n := int(upper - lower + 1)
+
+	result := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		if c.isSetOfArrays {
+			r.Discard(12)
+		}
+
+		elmLen := r.PopUint32()
+		if elmLen == 0xffffffff {
+			continue
+		}
+
+		val, err := c.child.decode(r.PopSlice(elmLen))
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = val
+	}
+
+	return result, nil
+}
+
+// dynamicTuple decodes tuples into []interface{}.
+type dynamicTuple struct {
+	elements []dynamicValue
+}
+
+func (c *dynamicTuple) decode(r *buff.Reader) (interface{}, error) {
+	elmCount := int(int32(r.PopUint32()))
+	if elmCount != len(c.elements) {
+		return nil, fmt.Errorf(
+			"wrong number of elements, expected %v got %v",
+			len(c.elements), elmCount)
+	}
+
+	result := make([]interface{}, elmCount)
+	for i, element := range c.elements {
+		r.Discard(4) // reserved
+
+		elmLen := r.PopUint32()
+		if elmLen == 0xffffffff {
+			continue
+		}
+
+		val, err := element.decode(r.PopSlice(elmLen))
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = val
+	}
+
+	return result, nil
+}
+
+func buildDynamicObjectV2(
+	desc *descriptor.V2,
+	asMaps bool,
+	path Path,
+) (dynamicValue, error) {
+	fields := make([]dynamicField, len(desc.Fields))
+	for i, field := range desc.Fields {
+		child, err := buildDynamicValueV2(
+			&field.Desc,
+			asMaps,
+			path.AddField(field.Name),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		fields[i] = dynamicField{
+			name:         field.Name,
+			implicit:     field.Implicit,
+			linkProperty: field.LinkProperty,
+			value:        child,
+		}
+	}
+
+	return &dynamicObject{fields: fields, asMap: asMaps}, nil
+}
+
+type dynamicField struct {
+	name         string
+	implicit     bool
+	linkProperty bool
+	value        dynamicValue
+}
+
+// dynamicObject decodes objects and named tuples into edgedb.Object
+// or map[string]interface{}. Implicit fields are not added to maps.
+type dynamicObject struct {
+	fields []dynamicField
+	asMap  bool
+}
+
+func (c *dynamicObject) decode(r *buff.Reader) (interface{}, error) {
+	elmCount := int(r.PopUint32())
+	if elmCount != len(c.fields) {
+		return nil, fmt.Errorf(
+			"wrong number of object fields: expected %v, got %v",
+			len(c.fields), elmCount)
+	}
+
+	var obj types.Object
+	var m map[string]interface{}
+	if c.asMap {
+		m = make(map[string]interface{}, elmCount)
+	} else {
+		obj.Fields = make([]types.ObjectField, elmCount)
+	}
+
+	for i, field := range c.fields {
+		r.Discard(4) // reserved
+
+		var val interface{}
+		elmLen := r.PopUint32()
+		// element length -1 means missing field
+		if elmLen != 0xffffffff {
+			var err error
+			val, err = field.value.decode(r.PopSlice(elmLen))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if c.asMap {
+			if !field.implicit {
+				m[field.name] = val
+			}
+			continue
+		}
+
+		obj.Fields[i] = types.ObjectField{
+			Name:         field.name,
+			Value:        val,
+			Implicit:     field.implicit,
+			LinkProperty: field.linkProperty,
+		}
+	}
+
+	if c.asMap {
+		return m, nil
+	}
+
+	return obj, nil
+}
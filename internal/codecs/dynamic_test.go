@@ -0,0 +1,219 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testObjectID = types.UUID{1}
+	testSetID    = types.UUID{2}
+)
+
+// testObjectDescriptor describes
+// SELECT User { name, nickname, tags, @weight }
+func testObjectDescriptor() descriptor.V2 {
+	str := descriptor.V2{Type: descriptor.Scalar, ID: StrID}
+	i64 := descriptor.V2{Type: descriptor.Scalar, ID: Int64ID}
+	return descriptor.V2{
+		Type: descriptor.Object,
+		ID:   testObjectID,
+		Fields: []*descriptor.FieldV2{
+			{
+				Name:     "id",
+				Desc:     descriptor.V2{Type: descriptor.Scalar, ID: UUIDID},
+				Required: true,
+				Implicit: true,
+			},
+			{Name: "name", Desc: str, Required: true},
+			{Name: "nickname", Desc: str},
+			{
+				Name: "tags",
+				Desc: descriptor.V2{
+					Type:   descriptor.Set,
+					ID:     testSetID,
+					Fields: []*descriptor.FieldV2{{Desc: str}},
+				},
+			},
+			{
+				Name:         "@weight",
+				Desc:         i64,
+				LinkProperty: true,
+			},
+		},
+	}
+}
+
+func appendElement(b []byte, data []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, 0) // reserved
+	if data == nil {
+		return binary.BigEndian.AppendUint32(b, 0xffffffff)
+	}
+
+	b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+func testObjectData() []byte {
+	id := types.UUID{0xff}
+	weight := binary.BigEndian.AppendUint64(nil, 7)
+
+	var tags []byte
+	tags = binary.BigEndian.AppendUint32(tags, 1) // dimensions
+	tags = binary.BigEndian.AppendUint64(tags, 0) // reserved
+	tags = binary.BigEndian.AppendUint32(tags, 2) // upper
+	tags = binary.BigEndian.AppendUint32(tags, 1) // lower
+	for _, tag := range []string{"a", "b"} {
+		tags = binary.BigEndian.AppendUint32(tags, uint32(len(tag)))
+		tags = append(tags, tag...)
+	}
+
+	data := binary.BigEndian.AppendUint32(nil, 5) // element count
+	data = appendElement(data, id[:])
+	data = appendElement(data, []byte("Alice"))
+	data = appendElement(data, nil)
+	data = appendElement(data, tags)
+	data = appendElement(data, weight)
+	return data
+}
+
+func TestDynamicDecodeObject(t *testing.T) {
+	desc := testObjectDescriptor()
+	decoder, err := BuildDecoderV2(&desc, objectType, "Object")
+	require.NoError(t, err)
+	assert.Equal(t, testObjectID, decoder.DescriptorID())
+
+	var result types.Object
+	r := buff.SimpleReader(testObjectData())
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	require.Empty(t, r.Buf)
+
+	expected := types.Object{Fields: []types.ObjectField{
+		{Name: "id", Value: types.UUID{0xff}, Implicit: true},
+		{Name: "name", Value: "Alice"},
+		{Name: "nickname", Value: nil},
+		{Name: "tags", Value: []interface{}{"a", "b"}},
+		{Name: "@weight", Value: int64(7), LinkProperty: true},
+	}}
+	assert.Equal(t, expected, result)
+
+	name, ok := result.Get("name")
+	assert.True(t, ok)
+	assert.Equal(t, "Alice", name)
+
+	_, ok = result.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestDynamicDecodeMap(t *testing.T) {
+	desc := testObjectDescriptor()
+	typ := reflect.TypeOf(map[string]interface{}{})
+	decoder, err := BuildDecoderV2(&desc, typ, "map")
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	r := buff.SimpleReader(testObjectData())
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+
+	expected := map[string]interface{}{
+		"name":     "Alice",
+		"nickname": nil,
+		"tags":     []interface{}{"a", "b"},
+		"@weight":  int64(7),
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestDynamicDecodeInterface(t *testing.T) {
+	desc := testObjectDescriptor()
+	typ := reflect.TypeOf((*interface{})(nil)).Elem()
+	decoder, err := BuildDecoderV2(&desc, typ, "interface")
+	require.NoError(t, err)
+
+	var result interface{}
+	r := buff.SimpleReader(testObjectData())
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	require.IsType(t, types.Object{}, result)
+	assert.Equal(t, 5, len(result.(types.Object).Fields))
+}
+
+func TestDynamicDecodeTuple(t *testing.T) {
+	str := descriptor.V2{Type: descriptor.Scalar, ID: StrID}
+	i64 := descriptor.V2{Type: descriptor.Scalar, ID: Int64ID}
+	typ := reflect.TypeOf((*interface{})(nil)).Elem()
+
+	data := binary.BigEndian.AppendUint32(nil, 2) // element count
+	data = appendElement(data, []byte("a"))
+	data = appendElement(data, binary.BigEndian.AppendUint64(nil, 1))
+
+	desc := descriptor.V2{
+		Type: descriptor.Tuple,
+		ID:   types.UUID{3},
+		Fields: []*descriptor.FieldV2{
+			{Name: "0", Desc: str},
+			{Name: "1", Desc: i64},
+		},
+	}
+	decoder, err := BuildDecoderV2(&desc, typ, "tuple")
+	require.NoError(t, err)
+
+	var result interface{}
+	r := buff.SimpleReader(data)
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	assert.Equal(t, []interface{}{"a", int64(1)}, result)
+
+	desc.Fields[0].Name = "str"
+	desc.Fields[1].Name = "int"
+	decoder, err = BuildDecoderV2(&desc, mapType, "named tuple")
+	require.NoError(t, err)
+
+	var m map[string]interface{}
+	r = buff.SimpleReader(data)
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&m)))
+	assert.Equal(t, map[string]interface{}{"str": "a", "int": int64(1)}, m)
+}
+
+func TestDynamicDecodeUnsupportedScalar(t *testing.T) {
+	desc := descriptor.V2{Type: descriptor.Scalar, ID: types.UUID{0xaa}}
+	typ := reflect.TypeOf((*interface{})(nil)).Elem()
+	_, err := BuildDecoderV2(&desc, typ, "unknown")
+	assert.EqualError(t, err, "cannot decode unknown dynamically: "+
+		"unsupported scalar type id aa000000-0000-0000-0000-000000000000")
+}
+
+func TestDynamicDecodeDecimal(t *testing.T) {
+	desc := descriptor.V2{Type: descriptor.Scalar, ID: DecimalID}
+	typ := reflect.TypeOf((*interface{})(nil)).Elem()
+	decoder, err := BuildDecoderV2(&desc, typ, "decimal")
+	require.NoError(t, err)
+
+	var result interface{}
+	r := buff.SimpleReader([]byte{0, 1, 0, 0, 0, 0, 0, 1, 0, 5})
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	require.IsType(t, types.Decimal{}, result)
+	assert.Equal(t, "5.0", result.(types.Decimal).String())
+}
@@ -18,10 +18,12 @@ package codecs
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
 	"unsafe"
 
+	"github.com/edgedb/edgedb-go/internal"
 	"github.com/edgedb/edgedb-go/internal/buff"
 	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
 	"github.com/edgedb/edgedb-go/internal/marshal"
@@ -212,22 +214,77 @@ func (c *optionalBigIntDecoder) DecodeMissing(out unsafe.Pointer) {
 
 func (c *optionalBigIntDecoder) DecodePresent(_ unsafe.Pointer) {}
 
-type decimalEncoder struct{}
+// DecimalCodec encodes/decodes edgedb.Decimal.
+type DecimalCodec struct{}
 
-func (c *decimalEncoder) DescriptorID() types.UUID { return DecimalID }
+// Type returns the type the codec encodes/decodes
+func (c *DecimalCodec) Type() reflect.Type { return decimalType }
+
+// DescriptorID returns the codecs descriptor id.
+func (c *DecimalCodec) DescriptorID() types.UUID { return DecimalID }
+
+// Decode decodes a decimal.
+func (c *DecimalCodec) Decode(r *buff.Reader, out unsafe.Pointer) error {
+	*(*types.Decimal)(out) = decodeDecimal(r)
+	return nil
+}
+
+func decodeDecimal(r *buff.Reader) types.Decimal {
+	n := int(r.PopUint16())
+	weight := int(int16(r.PopUint16()))
+	sign := r.PopUint16()
+	scale := int(r.PopUint16())
+
+	coef := &big.Int{}
+	digit := &big.Int{}
+	for i := 0; i < n; i++ {
+		digit.SetUint64(uint64(r.PopUint16()))
+		coef.Mul(coef, big10k)
+		coef.Add(coef, digit)
+	}
+
+	// coef is the decimal's value * 10^exp
+	exp := 0
+	if n > 0 {
+		exp = 4 * (n - 1 - weight)
+	}
+
+	switch {
+	case exp > scale:
+		coef.Quo(coef, internal.Pow10(exp-scale))
+	case exp < scale:
+		coef.Mul(coef, internal.Pow10(scale-exp))
+	}
+
+	if sign == 0x4000 {
+		coef.Neg(coef)
+	}
+
+	return types.NewDecimal(coef, scale)
+}
 
 type optionalDecimalMarshaler interface {
 	marshal.DecimalMarshaler
 	marshal.OptionalMarshaler
 }
 
-func (c *decimalEncoder) Encode(
+// Encode encodes a decimal.
+func (c *DecimalCodec) Encode(
 	w *buff.Writer,
 	val interface{},
 	path Path,
 	required bool,
 ) error {
 	switch in := val.(type) {
+	case types.Decimal:
+		return c.encodeData(w, in, path)
+	case types.OptionalDecimal:
+		data, ok := in.Get()
+		return encodeOptional(w, !ok, required,
+			func() error { return c.encodeData(w, data, path) },
+			func() error {
+				return missingValueError("edgedb.OptionalDecimal", path)
+			})
 	case optionalDecimalMarshaler:
 		return encodeOptional(w, in.Missing(), required,
 			func() error { return c.encodeMarshaler(w, in, path) },
@@ -235,12 +292,69 @@ func (c *decimalEncoder) Encode(
 	case marshal.DecimalMarshaler:
 		return c.encodeMarshaler(w, in, path)
 	default:
-		return fmt.Errorf("expected %v to be DecimalMarshaler got %T",
-			path, val)
+		return fmt.Errorf("expected %v to be edgedb.Decimal, "+
+			"edgedb.OptionalDecimal or DecimalMarshaler got %T", path, val)
 	}
 }
 
-func (c *decimalEncoder) encodeMarshaler(
+func (c *DecimalCodec) encodeData(
+	w *buff.Writer,
+	val types.Decimal,
+	path Path,
+) error {
+	scale := val.Scale()
+
+	// Pad the digits after the decimal point to a multiple of 4
+	// so that the base 10,000 digits are aligned with the decimal point.
+	pad := (4 - scale%4) % 4
+	n := val.Unscaled()
+	n.Mul(n, internal.Pow10(pad))
+
+	var sign uint16
+	if n.Sign() == -1 {
+		sign = 0x4000
+		n.Neg(n)
+	}
+
+	// digits are least significant first
+	var digits []uint16
+	rem := &big.Int{}
+	for n.Sign() != 0 {
+		n.QuoRem(n, big10k, rem)
+		digits = append(digits, uint16(rem.Uint64()))
+	}
+
+	weight := len(digits) - 1 - (scale+pad)/4
+
+	// trailing zeros are implied by the weight
+	for len(digits) > 0 && digits[0] == 0 {
+		digits = digits[1:]
+	}
+
+	if len(digits) == 0 {
+		weight = 0
+	}
+
+	if scale > math.MaxUint16 ||
+		len(digits) > math.MaxUint16 ||
+		weight > math.MaxInt16 ||
+		weight < math.MinInt16 {
+		return fmt.Errorf("%v is out of range for decimal: %v", path, val)
+	}
+
+	w.BeginBytes()
+	w.PushUint16(uint16(len(digits)))
+	w.PushUint16(uint16(int16(weight)))
+	w.PushUint16(sign)
+	w.PushUint16(uint16(scale))
+	for i := len(digits) - 1; i >= 0; i-- {
+		w.PushUint16(digits[i])
+	}
+	w.EndBytes()
+	return nil
+}
+
+func (c *DecimalCodec) encodeMarshaler(
 	w *buff.Writer,
 	val marshal.DecimalMarshaler,
 	path Path,
@@ -257,3 +371,30 @@ func (c *decimalEncoder) encodeMarshaler(
 	w.EndBytes()
 	return nil
 }
+
+type optionalDecimal struct {
+	val   types.Decimal
+	isSet bool
+}
+
+type optionalDecimalDecoder struct{}
+
+func (c *optionalDecimalDecoder) DescriptorID() types.UUID {
+	return DecimalID
+}
+
+func (c *optionalDecimalDecoder) Decode(
+	r *buff.Reader,
+	out unsafe.Pointer,
+) error {
+	opdec := (*optionalDecimal)(out)
+	opdec.val = decodeDecimal(r)
+	opdec.isSet = true
+	return nil
+}
+
+func (c *optionalDecimalDecoder) DecodeMissing(out unsafe.Pointer) {
+	(*types.OptionalDecimal)(out).Unset()
+}
+
+func (c *optionalDecimalDecoder) DecodePresent(_ unsafe.Pointer) {}
@@ -0,0 +1,95 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalCodec(t *testing.T) {
+	samples := []struct {
+		str  string
+		data []byte
+	}{
+		{"0", []byte{0, 0, 0, 0, 0, 0, 0, 0}},
+		{"0.00", []byte{0, 0, 0, 0, 0, 0, 0, 2}},
+		{"12.340", []byte{
+			0, 2, 0, 0, 0, 0, 0, 3,
+			0, 12, 0x0d, 0x48, // 12, 3400
+		}},
+		{"-12.340", []byte{
+			0, 2, 0, 0, 0x40, 0, 0, 3,
+			0, 12, 0x0d, 0x48, // 12, 3400
+		}},
+		{"0.0001", []byte{
+			0, 1, 0xff, 0xff, 0, 0, 0, 4,
+			0, 1,
+		}},
+		{"10000", []byte{
+			0, 1, 0, 1, 0, 0, 0, 0,
+			0, 1,
+		}},
+		{"123456789.987654321", []byte{
+			0, 6, 0, 2, 0, 0, 0, 9,
+			0x00, 0x01, 0x09, 0x29, 0x1a, 0x85, // 1, 2345, 6789
+			0x26, 0x94, 0x15, 0x38, 0x03, 0xe8, // 9876, 5432, 1000
+		}},
+	}
+
+	codec := &DecimalCodec{}
+	for _, s := range samples {
+		t.Run(s.str, func(t *testing.T) {
+			d, err := types.ParseDecimal(s.str)
+			require.NoError(t, err)
+
+			w := buff.NewWriter(nil)
+			w.BeginMessage(0)
+			require.NoError(t, codec.Encode(w, d, Path("decimal"), true))
+			w.EndMessage()
+			// skip the message header and the data length
+			assert.Equal(t, s.data, w.Unwrap()[9:])
+
+			var result types.Decimal
+			r := buff.SimpleReader(s.data)
+			require.NoError(t, codec.Decode(r, unsafe.Pointer(&result)))
+			assert.Equal(t, s.str, result.String())
+		})
+	}
+}
+
+func TestOptionalDecimalDecoder(t *testing.T) {
+	data := []byte{0, 1, 0, 0, 0, 0, 0, 1, 0, 5}
+
+	var result types.OptionalDecimal
+	decoder := &optionalDecimalDecoder{}
+	r := buff.SimpleReader(data)
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+
+	val, ok := result.Get()
+	assert.True(t, ok)
+	assert.Equal(t, "5.0", val.String())
+
+	decoder.DecodeMissing(unsafe.Pointer(&result))
+	_, ok = result.Get()
+	assert.False(t, ok)
+}
@@ -0,0 +1,142 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+)
+
+var optPkgPath = reflect.TypeOf(types.Optional{}).PkgPath()
+
+// optLayout returns the value type and the offset of the is set flag if typ
+// is an Opt or a struct that only embeds an Opt, like edgedb.Opt.
+func optLayout(typ reflect.Type) (reflect.Type, uintptr, bool) {
+	if typ == nil || typ.Kind() != reflect.Struct || isRegisteredType(typ) {
+		return nil, 0, false
+	}
+
+	for typ.Kind() == reflect.Struct &&
+		typ.NumField() == 1 &&
+		typ.Field(0).Anonymous {
+		typ = typ.Field(0).Type
+	}
+
+	if typ.Kind() != reflect.Struct ||
+		typ.PkgPath() != optPkgPath ||
+		!strings.HasPrefix(typ.Name(), "Opt[") {
+		return nil, 0, false
+	}
+
+	return typ.Field(0).Type, typ.Field(1).Offset, true
+}
+
+func buildOptDecoder(
+	desc descriptor.Descriptor,
+	typ reflect.Type,
+	path Path,
+) (Decoder, bool, error) {
+	elem, isSet, ok := optLayout(typ)
+	if !ok {
+		return nil, false, nil
+	}
+
+	child, err := BuildDecoder(desc, elem, path)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &optDecoder{child, elem, isSet}, true, nil
+}
+
+func buildOptDecoderV2(
+	desc *descriptor.V2,
+	typ reflect.Type,
+	path Path,
+) (Decoder, bool, error) {
+	elem, isSet, ok := optLayout(typ)
+	if !ok {
+		return nil, false, nil
+	}
+
+	child, err := BuildDecoderV2(desc, elem, path)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &optDecoder{child, elem, isSet}, true, nil
+}
+
+// optDecoder decodes an Opt using the decoder for its value type.
+type optDecoder struct {
+	child Decoder
+	typ   reflect.Type
+	isSet uintptr
+}
+
+func (c *optDecoder) DescriptorID() types.UUID {
+	return c.child.DescriptorID()
+}
+
+func (c *optDecoder) Decode(r *buff.Reader, out unsafe.Pointer) error {
+	if err := c.child.Decode(r, out); err != nil {
+		return err
+	}
+
+	*(*bool)(pAdd(out, c.isSet)) = true
+	return nil
+}
+
+func (c *optDecoder) DecodeMissing(out unsafe.Pointer) {
+	reflect.NewAt(c.typ, out).Elem().Set(reflect.Zero(c.typ))
+	*(*bool)(pAdd(out, c.isSet)) = false
+}
+
+// unwrapArg returns the value held by val if val is a pointer or an Opt.
+// ok is false if the value is missing.
+func unwrapArg(val interface{}) (interface{}, bool) {
+	// Only pointers and structs can hold optional values. Other arguments
+	// are returned right away without looking at the codec registry.
+	typ := reflect.TypeOf(val)
+	if typ == nil ||
+		(typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Struct) {
+		return val, true
+	}
+
+	for {
+		in, ok := derefArg(val)
+		if !ok {
+			return nil, false
+		}
+
+		if _, _, isOpt := optLayout(reflect.TypeOf(in)); !isOpt {
+			return in, true
+		}
+
+		result := reflect.ValueOf(in).MethodByName("Get").Call(nil)
+		if !result[1].Bool() {
+			return nil, false
+		}
+
+		val = result[0].Interface()
+	}
+}
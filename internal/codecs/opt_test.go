@@ -0,0 +1,153 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal"
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wrappedOpt is laid out like edgedb.Opt.
+type wrappedOpt[T any] struct {
+	types.Opt[T]
+}
+
+func TestOptDecoder(t *testing.T) {
+	type User struct {
+		ID       types.UUID         `edgedb:"id"`
+		Name     types.Opt[string]  `edgedb:"name"`
+		Nickname wrappedOpt[string] `edgedb:"nickname"`
+		Tags     []string           `edgedb:"tags"`
+		Weight   types.Opt[int64]   `edgedb:"@weight"`
+	}
+
+	desc := testObjectDescriptor()
+	typ := reflect.TypeOf(types.Opt[User]{})
+	decoder, err := BuildDecoderV2(&desc, typ, "User")
+	require.NoError(t, err)
+	assert.Equal(t, testObjectID, decoder.DescriptorID())
+
+	result := types.NewOpt(User{
+		Nickname: wrappedOpt[string]{types.NewOpt("Al")},
+	})
+	r := buff.SimpleReader(testObjectData())
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	require.Empty(t, r.Buf)
+
+	assert.Equal(t, types.NewOpt(User{
+		ID:     types.UUID{0xff},
+		Name:   types.NewOpt("Alice"),
+		Tags:   []string{"a", "b"},
+		Weight: types.NewOpt(int64(7)),
+	}), result)
+
+	decoder.(OptionalDecoder).DecodeMissing(unsafe.Pointer(&result))
+	assert.Equal(t, types.Opt[User]{}, result)
+}
+
+func TestOptDecoderRegisteredCodec(t *testing.T) {
+	desc := &descriptor.V2{
+		Type: descriptor.Scalar,
+		ID:   types.UUID{1},
+		Name: "test::ip_addr",
+	}
+
+	typ := reflect.TypeOf(types.Opt[netip.Addr]{})
+	decoder, err := BuildDecoderV2(desc, typ, "out")
+	require.NoError(t, err)
+
+	var result types.Opt[netip.Addr]
+	r := buff.SimpleReader([]byte("10.0.0.1"))
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	assert.Equal(t, types.NewOpt(netip.MustParseAddr("10.0.0.1")), result)
+
+	desc = &descriptor.V2{Type: descriptor.Scalar, ID: StrID}
+	_, err = BuildDecoderV2(desc, reflect.TypeOf(types.Opt[int64]{}), "out")
+	assert.EqualError(t, err,
+		"expected out to be string or edgedb.OptionalStr got int64")
+}
+
+func TestOptArgs(t *testing.T) {
+	str := descriptor.V2{Type: descriptor.Scalar, ID: StrID}
+	desc := &descriptor.V2{
+		Type: descriptor.Object,
+		ID:   types.UUID{1},
+		Fields: []*descriptor.FieldV2{
+			{Name: "0", Desc: str, Required: true},
+			{Name: "1", Desc: str},
+		},
+	}
+
+	encoder, err := BuildEncoderV2(desc, internal.ProtocolVersion{Major: 2})
+	require.NoError(t, err)
+
+	a := wrappedOpt[string]{types.NewOpt("a")}
+	data := encodeTestValue(t, encoder, []interface{}{a, types.Opt[string]{}})
+	assert.Equal(t, []byte{
+		0, 0, 0, 2, // element count
+		0, 0, 0, 0, // reserved
+		0, 0, 0, 1, // data length
+		'a',
+		0, 0, 0, 0, // reserved
+		0xff, 0xff, 0xff, 0xff, // missing
+	}, data)
+
+	w := buff.NewWriter(nil)
+	w.BeginMessage(0)
+	args := []interface{}{types.Opt[string]{}, a}
+	err = encoder.Encode(w, args, "args", true)
+	assert.EqualError(t, err, "cannot encode edgedbtypes.Opt[string] "+
+		"at args[0] because its value is missing")
+}
+
+func TestUnwrapArg(t *testing.T) {
+	str := "a"
+	var nilStr *string
+	samples := []struct {
+		name string
+		in   interface{}
+		out  interface{}
+		ok   bool
+	}{
+		{"nil", nil, nil, true},
+		{"scalar", int64(1), int64(1), true},
+		{"slice", []string{"a"}, []string{"a"}, true},
+		{"pointer", &str, "a", true},
+		{"nil pointer", nilStr, nil, false},
+		{"opt", types.NewOpt("a"), "a", true},
+		{"missing opt", types.Opt[string]{}, nil, false},
+		{"pointer to opt", &types.Opt[string]{}, nil, false},
+		{"registered type", netip.Addr{}, netip.Addr{}, true},
+	}
+
+	for _, s := range samples {
+		t.Run(s.name, func(t *testing.T) {
+			out, ok := unwrapArg(s.in)
+			assert.Equal(t, s.ok, ok)
+			assert.Equal(t, s.out, out)
+		})
+	}
+}
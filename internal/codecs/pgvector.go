@@ -0,0 +1,474 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+)
+
+// The pgvector types are defined by an extension so their type ids are not
+// fixed. They are recognized by name instead.
+const (
+	// VectorName is the ext::pgvector::vector type name.
+	VectorName = "ext::pgvector::vector"
+	// HalfVectorName is the ext::pgvector::halfvec type name.
+	HalfVectorName = "ext::pgvector::halfvec"
+	// SparseVectorName is the ext::pgvector::sparsevec type name.
+	SparseVectorName = "ext::pgvector::sparsevec"
+)
+
+// buildPGVectorEncoder returns an encoder for desc
+// if it is one of the pgvector types.
+func buildPGVectorEncoder(desc *descriptor.V2) (Encoder, bool) {
+	switch desc.Name {
+	case VectorName:
+		return &VectorCodec{desc.ID}, true
+	case HalfVectorName:
+		return &HalfVectorCodec{desc.ID}, true
+	case SparseVectorName:
+		return &SparseVectorCodec{desc.ID}, true
+	default:
+		return nil, false
+	}
+}
+
+// buildPGVectorDecoder returns a decoder for desc
+// if it is one of the pgvector types.
+func buildPGVectorDecoder(
+	desc *descriptor.V2,
+	typ reflect.Type,
+	path Path,
+) (Decoder, bool, error) {
+	var expectedType string
+
+	switch desc.Name {
+	case VectorName:
+		switch typ {
+		case float32SliceType:
+			return &VectorCodec{desc.ID}, true, nil
+		case optionalVectorType:
+			return &optionalVectorDecoder{desc.ID}, true, nil
+		default:
+			expectedType = "[]float32 or edgedb.OptionalVector"
+		}
+	case HalfVectorName:
+		switch typ {
+		case float32SliceType:
+			return &HalfVectorCodec{desc.ID}, true, nil
+		case optionalVectorType:
+			return &optionalHalfVectorDecoder{desc.ID}, true, nil
+		default:
+			expectedType = "[]float32 or edgedb.OptionalVector"
+		}
+	case SparseVectorName:
+		switch typ {
+		case sparseVectorType:
+			return &SparseVectorCodec{desc.ID}, true, nil
+		case optionalSparseVectorType:
+			return &optionalSparseVectorDecoder{desc.ID}, true, nil
+		default:
+			expectedType = "edgedb.SparseVector or " +
+				"edgedb.OptionalSparseVector"
+		}
+	default:
+		return nil, false, nil
+	}
+
+	return nil, false, fmt.Errorf(
+		"expected %v to be %v got %v", path, expectedType, typ,
+	)
+}
+
+// VectorCodec encodes/decodes ext::pgvector::vector values.
+type VectorCodec struct {
+	ID types.UUID
+}
+
+// Type returns the type the codec encodes/decodes
+func (c *VectorCodec) Type() reflect.Type { return float32SliceType }
+
+// DescriptorID returns the codecs descriptor id.
+func (c *VectorCodec) DescriptorID() types.UUID { return c.ID }
+
+// Decode decodes a value
+func (c *VectorCodec) Decode(r *buff.Reader, out unsafe.Pointer) error {
+	*(*[]float32)(out) = decodeVector(r)
+	return nil
+}
+
+func decodeVector(r *buff.Reader) []float32 {
+	n := int(r.PopUint16())
+	r.Discard(2) // unused
+
+	vec := make([]float32, n)
+	for i := range vec {
+		vec[i] = math.Float32frombits(r.PopUint32())
+	}
+
+	return vec
+}
+
+// Encode encodes a value
+func (c *VectorCodec) Encode(
+	w *buff.Writer,
+	val interface{},
+	path Path,
+	required bool,
+) error {
+	switch in := val.(type) {
+	case []float32:
+		return c.encodeData(w, in, path)
+	case types.OptionalVector:
+		data, ok := in.Get()
+		return encodeOptional(w, !ok, required,
+			func() error { return c.encodeData(w, data, path) },
+			func() error {
+				return missingValueError("edgedb.OptionalVector", path)
+			})
+	default:
+		return fmt.Errorf("expected %v to be []float32 or "+
+			"edgedb.OptionalVector got %T", path, val)
+	}
+}
+
+func (c *VectorCodec) encodeData(
+	w *buff.Writer,
+	data []float32,
+	path Path,
+) error {
+	if len(data) > math.MaxUint16 {
+		return fmt.Errorf("cannot encode %v: vectors can not have more "+
+			"than %v dimensions", path, math.MaxUint16)
+	}
+
+	w.PushUint32(uint32(4 + 4*len(data)))
+	w.PushUint16(uint16(len(data)))
+	w.PushUint16(0) // unused
+	for _, v := range data {
+		w.PushUint32(math.Float32bits(v))
+	}
+
+	return nil
+}
+
+type optionalVector struct {
+	val []float32
+	set bool
+}
+
+type optionalVectorDecoder struct {
+	id types.UUID
+}
+
+func (c *optionalVectorDecoder) DescriptorID() types.UUID { return c.id }
+
+func (c *optionalVectorDecoder) Decode(
+	r *buff.Reader,
+	out unsafe.Pointer,
+) error {
+	opvec := (*optionalVector)(out)
+	opvec.val = decodeVector(r)
+	opvec.set = true
+	return nil
+}
+
+func (c *optionalVectorDecoder) DecodeMissing(out unsafe.Pointer) {
+	(*types.OptionalVector)(out).Unset()
+}
+
+func (c *optionalVectorDecoder) DecodePresent(_ unsafe.Pointer) {}
+
+// HalfVectorCodec encodes/decodes ext::pgvector::halfvec values.
+// Elements are half precision floats on the wire
+// and are converted to and from float32.
+type HalfVectorCodec struct {
+	ID types.UUID
+}
+
+// Type returns the type the codec encodes/decodes
+func (c *HalfVectorCodec) Type() reflect.Type { return float32SliceType }
+
+// DescriptorID returns the codecs descriptor id.
+func (c *HalfVectorCodec) DescriptorID() types.UUID { return c.ID }
+
+// Decode decodes a value
+func (c *HalfVectorCodec) Decode(r *buff.Reader, out unsafe.Pointer) error {
+	*(*[]float32)(out) = decodeHalfVector(r)
+	return nil
+}
+
+func decodeHalfVector(r *buff.Reader) []float32 {
+	n := int(r.PopUint16())
+	r.Discard(2) // unused
+
+	vec := make([]float32, n)
+	for i := range vec {
+		vec[i] = float16ToFloat32(r.PopUint16())
+	}
+
+	return vec
+}
+
+// Encode encodes a value
+func (c *HalfVectorCodec) Encode(
+	w *buff.Writer,
+	val interface{},
+	path Path,
+	required bool,
+) error {
+	switch in := val.(type) {
+	case []float32:
+		return c.encodeData(w, in, path)
+	case types.OptionalVector:
+		data, ok := in.Get()
+		return encodeOptional(w, !ok, required,
+			func() error { return c.encodeData(w, data, path) },
+			func() error {
+				return missingValueError("edgedb.OptionalVector", path)
+			})
+	default:
+		return fmt.Errorf("expected %v to be []float32 or "+
+			"edgedb.OptionalVector got %T", path, val)
+	}
+}
+
+func (c *HalfVectorCodec) encodeData(
+	w *buff.Writer,
+	data []float32,
+	path Path,
+) error {
+	if len(data) > math.MaxUint16 {
+		return fmt.Errorf("cannot encode %v: vectors can not have more "+
+			"than %v dimensions", path, math.MaxUint16)
+	}
+
+	halfs := make([]uint16, len(data))
+	for i, v := range data {
+		h, ok := float32ToFloat16(v)
+		if !ok {
+			return fmt.Errorf("cannot encode %v: %v is out of range "+
+				"for a half precision float", path.AddIndex(i), v)
+		}
+		halfs[i] = h
+	}
+
+	w.PushUint32(uint32(4 + 2*len(data)))
+	w.PushUint16(uint16(len(data)))
+	w.PushUint16(0) // unused
+	for _, h := range halfs {
+		w.PushUint16(h)
+	}
+
+	return nil
+}
+
+type optionalHalfVectorDecoder struct {
+	id types.UUID
+}
+
+func (c *optionalHalfVectorDecoder) DescriptorID() types.UUID { return c.id }
+
+func (c *optionalHalfVectorDecoder) Decode(
+	r *buff.Reader,
+	out unsafe.Pointer,
+) error {
+	opvec := (*optionalVector)(out)
+	opvec.val = decodeHalfVector(r)
+	opvec.set = true
+	return nil
+}
+
+func (c *optionalHalfVectorDecoder) DecodeMissing(out unsafe.Pointer) {
+	(*types.OptionalVector)(out).Unset()
+}
+
+func (c *optionalHalfVectorDecoder) DecodePresent(_ unsafe.Pointer) {}
+
+// float16ToFloat32 converts an IEEE 754 half precision float to a float32.
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h & 0x3ff)
+
+	switch exp {
+	case 0:
+		// zero or subnormal
+		f := float32(frac) / (1 << 24)
+		return math.Float32frombits(math.Float32bits(f) | sign)
+	case 0x1f:
+		// infinity or NaN
+		return math.Float32frombits(sign | 0x7f800000 | frac<<13)
+	default:
+		return math.Float32frombits(sign | (exp+112)<<23 | frac<<13)
+	}
+}
+
+// float32ToFloat16 converts f to an IEEE 754 half precision float rounding
+// to the nearest even value. It returns false if f is finite but too large.
+func float32ToFloat16(f float32) (uint16, bool) {
+	sign := uint16(math.Float32bits(f)>>16) & 0x8000
+	a := math.Abs(float64(f))
+
+	switch {
+	case math.IsNaN(a):
+		return sign | 0x7e00, true
+	case math.IsInf(a, 0):
+		return sign | 0x7c00, true
+	case a >= 65520:
+		// rounds to infinity
+		return 0, false
+	case a < 1.0/(1<<14):
+		// subnormal, rounding up to 0x400 gives the smallest normal
+		return sign | uint16(math.RoundToEven(a*(1<<24))), true
+	}
+
+	frac, exp := math.Frexp(a)
+	mantissa := uint16(math.RoundToEven((2*frac - 1) * 1024))
+	biased := uint16(exp - 1 + 15)
+	if mantissa == 1024 {
+		mantissa = 0
+		biased++
+	}
+
+	return sign | biased<<10 | mantissa, true
+}
+
+// SparseVectorCodec encodes/decodes ext::pgvector::sparsevec values.
+type SparseVectorCodec struct {
+	ID types.UUID
+}
+
+// Type returns the type the codec encodes/decodes
+func (c *SparseVectorCodec) Type() reflect.Type { return sparseVectorType }
+
+// DescriptorID returns the codecs descriptor id.
+func (c *SparseVectorCodec) DescriptorID() types.UUID { return c.ID }
+
+// Decode decodes a value
+func (c *SparseVectorCodec) Decode(r *buff.Reader, out unsafe.Pointer) error {
+	vec, err := decodeSparseVector(r)
+	if err != nil {
+		return err
+	}
+
+	*(*types.SparseVector)(out) = vec
+	return nil
+}
+
+func decodeSparseVector(r *buff.Reader) (types.SparseVector, error) {
+	dim := int(int32(r.PopUint32()))
+	nnz := int(int32(r.PopUint32()))
+	r.Discard(4) // unused
+
+	indices := make([]int, nnz)
+	for i := range indices {
+		indices[i] = int(int32(r.PopUint32()))
+	}
+
+	elements := make(map[int]float32, nnz)
+	for _, i := range indices {
+		elements[i] = math.Float32frombits(r.PopUint32())
+	}
+
+	return types.NewSparseVector(dim, elements)
+}
+
+// Encode encodes a value
+func (c *SparseVectorCodec) Encode(
+	w *buff.Writer,
+	val interface{},
+	path Path,
+	required bool,
+) error {
+	switch in := val.(type) {
+	case types.SparseVector:
+		return c.encodeData(w, in)
+	case types.OptionalSparseVector:
+		data, ok := in.Get()
+		return encodeOptional(w, !ok, required,
+			func() error { return c.encodeData(w, data) },
+			func() error {
+				return missingValueError(
+					"edgedb.OptionalSparseVector", path)
+			})
+	default:
+		return fmt.Errorf("expected %v to be edgedb.SparseVector or "+
+			"edgedb.OptionalSparseVector got %T", path, val)
+	}
+}
+
+func (c *SparseVectorCodec) encodeData(
+	w *buff.Writer,
+	data types.SparseVector,
+) error {
+	indices := data.Indices()
+	values := data.Values()
+
+	w.PushUint32(uint32(12 + 8*len(indices)))
+	w.PushUint32(uint32(data.Dim()))
+	w.PushUint32(uint32(len(indices)))
+	w.PushUint32(0) // unused
+	for _, i := range indices {
+		w.PushUint32(uint32(i))
+	}
+	for _, v := range values {
+		w.PushUint32(math.Float32bits(v))
+	}
+
+	return nil
+}
+
+type optionalSparseVector struct {
+	val types.SparseVector
+	set bool
+}
+
+type optionalSparseVectorDecoder struct {
+	id types.UUID
+}
+
+func (c *optionalSparseVectorDecoder) DescriptorID() types.UUID {
+	return c.id
+}
+
+func (c *optionalSparseVectorDecoder) Decode(
+	r *buff.Reader,
+	out unsafe.Pointer,
+) error {
+	vec, err := decodeSparseVector(r)
+	if err != nil {
+		return err
+	}
+
+	opvec := (*optionalSparseVector)(out)
+	opvec.val = vec
+	opvec.set = true
+	return nil
+}
+
+func (c *optionalSparseVectorDecoder) DecodeMissing(out unsafe.Pointer) {
+	(*types.OptionalSparseVector)(out).Unset()
+}
+
+func (c *optionalSparseVectorDecoder) DecodePresent(_ unsafe.Pointer) {}
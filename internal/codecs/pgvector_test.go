@@ -0,0 +1,187 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"math"
+	"testing"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal"
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestValue(t *testing.T, encoder Encoder, val interface{}) []byte {
+	w := buff.NewWriter(nil)
+	w.BeginMessage(0)
+	require.NoError(t, encoder.Encode(w, val, Path("args[0]"), true))
+	w.EndMessage()
+	// skip the message header and the data length
+	return w.Unwrap()[9:]
+}
+
+func TestVectorCodec(t *testing.T) {
+	data := []byte{
+		0, 3, 0, 0, // dimensions, unused
+		0x3f, 0x80, 0, 0, // 1
+		0xc0, 0, 0, 0, // -2
+		0x3e, 0x80, 0, 0, // 0.25
+	}
+
+	codec := &VectorCodec{}
+	vec := []float32{1, -2, 0.25}
+	assert.Equal(t, data, encodeTestValue(t, codec, vec))
+	assert.Equal(t, data, encodeTestValue(t, codec,
+		types.NewOptionalVector(vec)))
+
+	var result []float32
+	r := buff.SimpleReader(data)
+	require.NoError(t, codec.Decode(r, unsafe.Pointer(&result)))
+	assert.Equal(t, vec, result)
+
+	var optional types.OptionalVector
+	decoder := &optionalVectorDecoder{}
+	r = buff.SimpleReader(data)
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&optional)))
+	assert.Equal(t, types.NewOptionalVector(vec), optional)
+
+	decoder.DecodeMissing(unsafe.Pointer(&optional))
+	assert.Equal(t, types.OptionalVector{}, optional)
+}
+
+func TestHalfVectorCodec(t *testing.T) {
+	data := []byte{
+		0, 3, 0, 0, // dimensions, unused
+		0x3c, 0x00, // 1
+		0xc0, 0x00, // -2
+		0x34, 0x00, // 0.25
+	}
+
+	codec := &HalfVectorCodec{}
+	vec := []float32{1, -2, 0.25}
+	assert.Equal(t, data, encodeTestValue(t, codec, vec))
+
+	var result []float32
+	r := buff.SimpleReader(data)
+	require.NoError(t, codec.Decode(r, unsafe.Pointer(&result)))
+	assert.Equal(t, vec, result)
+
+	w := buff.NewWriter(nil)
+	w.BeginMessage(0)
+	err := codec.Encode(w, []float32{1, 1e6}, Path("args[0]"), true)
+	assert.EqualError(t, err, "cannot encode args[0][1]: "+
+		"1e+06 is out of range for a half precision float")
+}
+
+func TestFloat16(t *testing.T) {
+	samples := []struct {
+		f float32
+		h uint16
+	}{
+		{0, 0x0000},
+		{float32(math.Copysign(0, -1)), 0x8000},
+		{1, 0x3c00},
+		{-1, 0xbc00},
+		{0.5, 0x3800},
+		{1.5, 0x3e00},
+		{65504, 0x7bff},
+		{float32(math.Inf(1)), 0x7c00},
+		{float32(math.Inf(-1)), 0xfc00},
+		{6.103515625e-05, 0x0400},  // smallest normal
+		{5.960464477539063e-08, 1}, // smallest subnormal
+	}
+
+	for _, s := range samples {
+		h, ok := float32ToFloat16(s.f)
+		assert.True(t, ok)
+		assert.Equal(t, s.h, h, "encoding %v", s.f)
+		assert.Equal(t, s.f, float16ToFloat32(s.h), "decoding %x", s.h)
+	}
+
+	// 1 + 2^-11 is half way between 1 and the next half, round to even
+	h, ok := float32ToFloat16(1 + 1.0/(1<<11))
+	assert.True(t, ok)
+	assert.Equal(t, uint16(0x3c00), h)
+
+	h, ok = float32ToFloat16(float32(math.NaN()))
+	assert.True(t, ok)
+	assert.True(t, math.IsNaN(float64(float16ToFloat32(h))))
+
+	_, ok = float32ToFloat16(65520)
+	assert.False(t, ok)
+}
+
+func TestSparseVectorCodec(t *testing.T) {
+	data := []byte{
+		0, 0, 0, 5, // dimensions
+		0, 0, 0, 2, // non zero elements
+		0, 0, 0, 0, // unused
+		0, 0, 0, 1, // index
+		0, 0, 0, 4, // index
+		0x3f, 0x80, 0, 0, // 1
+		0xc0, 0, 0, 0, // -2
+	}
+
+	codec := &SparseVectorCodec{}
+	vec := types.NewSparseVectorFromSlice([]float32{0, 1, 0, 0, -2})
+	assert.Equal(t, data, encodeTestValue(t, codec, vec))
+
+	var result types.SparseVector
+	r := buff.SimpleReader(data)
+	require.NoError(t, codec.Decode(r, unsafe.Pointer(&result)))
+	assert.Equal(t, vec, result)
+
+	var optional types.OptionalSparseVector
+	decoder := &optionalSparseVectorDecoder{}
+	r = buff.SimpleReader(data)
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&optional)))
+	assert.Equal(t, types.NewOptionalSparseVector(vec), optional)
+}
+
+func TestBuildPGVectorDecoder(t *testing.T) {
+	id := types.UUID{1, 2, 3}
+	desc := &descriptor.V2{
+		Type: descriptor.Scalar,
+		ID:   types.UUID{4, 5, 6},
+		Name: "default::embedding",
+		Ancestors: []*descriptor.FieldV2{{Desc: descriptor.V2{
+			Type: descriptor.Scalar,
+			ID:   id,
+			Name: VectorName,
+		}}},
+	}
+
+	decoder, err := BuildDecoderV2(desc, float32SliceType, Path("out"))
+	require.NoError(t, err)
+	assert.Equal(t, &VectorCodec{id}, decoder)
+
+	decoder, err = BuildDecoderV2(desc, interfaceType, Path("out"))
+	require.NoError(t, err)
+	assert.Equal(t, desc.ID, decoder.DescriptorID())
+
+	_, err = BuildDecoderV2(desc, strType, Path("out"))
+	assert.EqualError(t, err,
+		"expected out to be []float32 or edgedb.OptionalVector got string")
+
+	encoder, err := BuildEncoderV2(desc, internal.ProtocolVersion{Major: 2})
+	require.NoError(t, err)
+	assert.Equal(t, &VectorCodec{id}, encoder)
+}
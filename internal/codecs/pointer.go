@@ -0,0 +1,184 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+)
+
+// isPointerType returns true if values of typ are decoded
+// by allocating a new typ.Elem() and nil means missing.
+func isPointerType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Ptr &&
+		typ != bigIntType &&
+		!isRegisteredType(typ)
+}
+
+func buildPointerDecoder(
+	desc descriptor.Descriptor,
+	typ reflect.Type,
+	path Path,
+) (Decoder, bool, error) {
+	if isNilableScalar(desc.Type, typ) {
+		decoder, err := buildScalarDecoder(desc, typ, path)
+		if err != nil {
+			return nil, true, err
+		}
+
+		return &nilableDecoder{decoder}, true, nil
+	}
+
+	if !isPointerType(typ) {
+		return nil, false, nil
+	}
+
+	child, err := BuildDecoder(desc, typ.Elem(), path)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &pointerDecoder{child, typ}, true, nil
+}
+
+func buildPointerDecoderV2(
+	desc *descriptor.V2,
+	typ reflect.Type,
+	path Path,
+) (Decoder, bool, error) {
+	if isNilableScalar(desc.Type, typ) {
+		decoder, err := buildScalarDecoderV2(desc, typ, path)
+		if err != nil {
+			return nil, true, err
+		}
+
+		return &nilableDecoder{decoder}, true, nil
+	}
+
+	if !isPointerType(typ) {
+		return nil, false, nil
+	}
+
+	child, err := BuildDecoderV2(desc, typ.Elem(), path)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &pointerDecoder{child, typ}, true, nil
+}
+
+// isNilableScalar returns true if typ is a pointer that scalar decoders
+// decode into directly.
+func isNilableScalar(descType descriptor.Type, typ reflect.Type) bool {
+	switch descType {
+	case descriptor.BaseScalar, descriptor.Scalar:
+		return typ == bigIntType && !isRegisteredType(typ)
+	default:
+		return false
+	}
+}
+
+// pointerDecoder decodes into a newly allocated value
+// and sets missing values to nil.
+type pointerDecoder struct {
+	child Decoder
+	typ   reflect.Type
+}
+
+func (c *pointerDecoder) DescriptorID() types.UUID {
+	return c.child.DescriptorID()
+}
+
+func (c *pointerDecoder) Decode(r *buff.Reader, out unsafe.Pointer) error {
+	val := reflect.New(c.typ.Elem())
+	if err := c.child.Decode(r, val.UnsafePointer()); err != nil {
+		return err
+	}
+
+	reflect.NewAt(c.typ, out).Elem().Set(val)
+	return nil
+}
+
+func (c *pointerDecoder) DecodeMissing(out unsafe.Pointer) {
+	*(*unsafe.Pointer)(out) = nil
+}
+
+// nilableDecoder sets missing values to nil
+// for decoders that already decode into a pointer.
+type nilableDecoder struct {
+	Decoder
+}
+
+func (c *nilableDecoder) DecodeMissing(out unsafe.Pointer) {
+	*(*unsafe.Pointer)(out) = nil
+}
+
+// derefArg returns the value that val points to. ok is false if val is a nil
+// pointer. Pointers that are handled by an encoder directly are returned
+// unchanged, like *big.Int, registered types and custom marshalers.
+func derefArg(val interface{}) (interface{}, bool) {
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Ptr {
+		return val, true
+	}
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+
+		if !isPointerType(v.Type()) || hasMarshalerMethod(v.Type()) {
+			return v.Interface(), true
+		}
+
+		v = v.Elem()
+	}
+
+	return v.Interface(), true
+}
+
+func hasMarshalerMethod(typ reflect.Type) bool {
+	for i := 0; i < typ.NumMethod(); i++ {
+		name := typ.Method(i).Name
+		if name == "Missing" || strings.HasPrefix(name, "MarshalEdgeDB") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encode encodes val with the field's encoder.
+// A nil pointer or an unset Opt is encoded as a missing value.
+func (f *EncoderField) encode(
+	w *buff.Writer,
+	val interface{},
+	path Path,
+) error {
+	in, ok := unwrapArg(val)
+	if !ok {
+		return encodeOptional(w, true, f.required, nil,
+			func() error { return missingValueError(val, path) })
+	}
+
+	return f.encoder.Encode(w, in, path, f.required)
+}
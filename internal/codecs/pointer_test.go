@@ -0,0 +1,114 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal"
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointerDecoder(t *testing.T) {
+	type User struct {
+		ID       *types.UUID `edgedb:"id"`
+		Name     *string     `edgedb:"name"`
+		Nickname *string     `edgedb:"nickname"`
+		Tags     []*string   `edgedb:"tags"`
+		Weight   *int64      `edgedb:"@weight"`
+	}
+
+	desc := testObjectDescriptor()
+	decoder, err := BuildDecoderV2(&desc, reflect.TypeOf(&User{}), "User")
+	require.NoError(t, err)
+	assert.Equal(t, testObjectID, decoder.DescriptorID())
+
+	nickname := "Al"
+	result := &User{Nickname: &nickname}
+	r := buff.SimpleReader(testObjectData())
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	require.Empty(t, r.Buf)
+
+	id := types.UUID{0xff}
+	name := "Alice"
+	a, b := "a", "b"
+	weight := int64(7)
+	assert.Equal(t, &User{
+		ID:     &id,
+		Name:   &name,
+		Tags:   []*string{&a, &b},
+		Weight: &weight,
+	}, result)
+	assert.Equal(t, "Al", nickname, "the old value must not be mutated")
+
+	decoder.(OptionalDecoder).DecodeMissing(unsafe.Pointer(&result))
+	assert.Nil(t, result)
+}
+
+func TestPointerDecoderBigInt(t *testing.T) {
+	desc := &descriptor.V2{Type: descriptor.Scalar, ID: BigIntID}
+	decoder, err := BuildDecoderV2(desc, bigIntType, "out")
+	require.NoError(t, err)
+
+	result := big.NewInt(1)
+	r := buff.SimpleReader([]byte{0, 1, 0, 0, 0, 0, 0, 0, 0, 2})
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	assert.Equal(t, big.NewInt(2), result)
+
+	decoder.(OptionalDecoder).DecodeMissing(unsafe.Pointer(&result))
+	assert.Nil(t, result)
+}
+
+func TestPointerArgs(t *testing.T) {
+	str := descriptor.V2{Type: descriptor.Scalar, ID: StrID}
+	desc := &descriptor.V2{
+		Type: descriptor.Object,
+		ID:   types.UUID{1},
+		Fields: []*descriptor.FieldV2{
+			{Name: "0", Desc: str, Required: true},
+			{Name: "1", Desc: str},
+		},
+	}
+
+	encoder, err := BuildEncoderV2(desc, internal.ProtocolVersion{Major: 2})
+	require.NoError(t, err)
+
+	a := "a"
+	var missing *string
+	data := encodeTestValue(t, encoder, []interface{}{&a, missing})
+	assert.Equal(t, []byte{
+		0, 0, 0, 2, // element count
+		0, 0, 0, 0, // reserved
+		0, 0, 0, 1, // data length
+		'a',
+		0, 0, 0, 0, // reserved
+		0xff, 0xff, 0xff, 0xff, // missing
+	}, data)
+
+	w := buff.NewWriter(nil)
+	w.BeginMessage(0)
+	err = encoder.Encode(w, []interface{}{missing, &a}, "args", true)
+	assert.EqualError(t, err,
+		"cannot encode *string at args[0] because its value is missing")
+}
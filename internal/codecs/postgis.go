@@ -0,0 +1,197 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+)
+
+// The PostGIS types are defined by an extension so their type ids are not
+// fixed. They are recognized by name instead.
+const (
+	// GeometryName is the ext::postgis::geometry type name.
+	GeometryName = "ext::postgis::geometry"
+	// GeographyName is the ext::postgis::geography type name.
+	GeographyName = "ext::postgis::geography"
+	// Box2DName is the ext::postgis::box2d type name.
+	Box2DName = "ext::postgis::box2d"
+	// Box3DName is the ext::postgis::box3d type name.
+	Box3DName = "ext::postgis::box3d"
+)
+
+// postGISTypes are the go types for each PostGIS type name
+// followed by their optional go type.
+var postGISTypes = map[string][2]reflect.Type{
+	GeometryName:  {geometryType, optionalGeometryType},
+	GeographyName: {geographyType, optionalGeographyType},
+	Box2DName:     {box2DType, optionalBox2DType},
+	Box3DName:     {box3DType, optionalBox3DType},
+}
+
+// buildPostGISEncoder returns an encoder for desc
+// if it is one of the PostGIS types.
+func buildPostGISEncoder(desc *descriptor.V2) (Encoder, bool) {
+	typs, ok := postGISTypes[desc.Name]
+	if !ok {
+		return nil, false
+	}
+
+	return &PostGISCodec{ID: desc.ID, typ: typs[0], optionalTyp: typs[1]}, true
+}
+
+// buildPostGISDecoder returns a decoder for desc
+// if it is one of the PostGIS types.
+func buildPostGISDecoder(
+	desc *descriptor.V2,
+	typ reflect.Type,
+	path Path,
+) (Decoder, bool, error) {
+	typs, ok := postGISTypes[desc.Name]
+	if !ok {
+		return nil, false, nil
+	}
+
+	switch typ {
+	case typs[0]:
+		return &PostGISCodec{
+			ID:          desc.ID,
+			typ:         typs[0],
+			optionalTyp: typs[1],
+		}, true, nil
+	case typs[1]:
+		return &optionalPostGISDecoder{id: desc.ID}, true, nil
+	default:
+		return nil, false, fmt.Errorf(
+			"expected %v to be edgedb.%v or edgedb.%v got %v",
+			path, typs[0].Name(), typs[1].Name(), typ,
+		)
+	}
+}
+
+// PostGISCodec encodes/decodes PostGIS geometry, geography and box values.
+// Values are sent as well-known binary.
+type PostGISCodec struct {
+	ID          types.UUID
+	typ         reflect.Type
+	optionalTyp reflect.Type
+}
+
+// Type returns the type the codec encodes/decodes
+func (c *PostGISCodec) Type() reflect.Type { return c.typ }
+
+// DescriptorID returns the codecs descriptor id.
+func (c *PostGISCodec) DescriptorID() types.UUID { return c.ID }
+
+// Decode decodes a value
+func (c *PostGISCodec) Decode(r *buff.Reader, out unsafe.Pointer) error {
+	// All of the PostGIS types have a single []byte field.
+	*(*[]byte)(out) = popWKB(r)
+	return nil
+}
+
+// popWKB always allocates because the PostGIS types share their
+// bytes when they are copied.
+func popWKB(r *buff.Reader) []byte {
+	wkb := make([]byte, len(r.Buf))
+	copy(wkb, r.Buf)
+	r.Discard(len(r.Buf))
+	return wkb
+}
+
+// Encode encodes a value
+func (c *PostGISCodec) Encode(
+	w *buff.Writer,
+	val interface{},
+	path Path,
+	required bool,
+) error {
+	var (
+		data    []byte
+		missing bool
+	)
+
+	switch in := val.(type) {
+	case types.Geometry:
+		data = in.WKB()
+	case types.OptionalGeometry:
+		v, ok := in.Get()
+		data, missing = v.WKB(), !ok
+	case types.Geography:
+		data = in.WKB()
+	case types.OptionalGeography:
+		v, ok := in.Get()
+		data, missing = v.WKB(), !ok
+	case types.Box2D:
+		data = in.WKB()
+	case types.OptionalBox2D:
+		v, ok := in.Get()
+		data, missing = v.WKB(), !ok
+	case types.Box3D:
+		data = in.WKB()
+	case types.OptionalBox3D:
+		v, ok := in.Get()
+		data, missing = v.WKB(), !ok
+	}
+
+	valType := reflect.TypeOf(val)
+	if valType != c.typ && valType != c.optionalTyp {
+		return fmt.Errorf("expected %v to be edgedb.%v or edgedb.%v got %T",
+			path, c.typ.Name(), c.optionalTyp.Name(), val)
+	}
+
+	return encodeOptional(w, missing, required,
+		func() error {
+			w.PushUint32(uint32(len(data)))
+			w.PushBytes(data)
+			return nil
+		},
+		func() error {
+			return missingValueError("edgedb."+c.optionalTyp.Name(), path)
+		})
+}
+
+type optionalPostGISDecoder struct {
+	id types.UUID
+}
+
+func (c *optionalPostGISDecoder) DescriptorID() types.UUID { return c.id }
+
+func (c *optionalPostGISDecoder) Decode(
+	r *buff.Reader,
+	out unsafe.Pointer,
+) error {
+	// All of the optional PostGIS types have the same layout
+	// as the optional bytes type.
+	opwkb := (*optionalBytesLayout)(out)
+	opwkb.val = popWKB(r)
+	opwkb.set = true
+	return nil
+}
+
+func (c *optionalPostGISDecoder) DecodeMissing(out unsafe.Pointer) {
+	opwkb := (*optionalBytesLayout)(out)
+	opwkb.val = nil
+	opwkb.set = false
+}
+
+func (c *optionalPostGISDecoder) DecodePresent(_ unsafe.Pointer) {}
@@ -0,0 +1,110 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal"
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostGISCodec(t *testing.T) {
+	desc := &descriptor.V2{
+		Type: descriptor.Scalar,
+		ID:   types.UUID{1, 2, 3},
+		Name: GeometryName,
+	}
+
+	geometry := types.NewGeometry(types.Point{X: 1, Y: 2})
+	data := geometry.WKB()
+
+	encoder, err := BuildEncoderV2(desc, internal.ProtocolVersion{Major: 2})
+	require.NoError(t, err)
+	assert.Equal(t, data, encodeTestValue(t, encoder, geometry))
+	assert.Equal(t, data, encodeTestValue(t, encoder,
+		types.NewOptionalGeometry(geometry)))
+
+	w := buff.NewWriter(nil)
+	w.BeginMessage(0)
+	err = encoder.Encode(w, types.Geography{}, Path("args[0]"), true)
+	assert.EqualError(t, err, "expected args[0] to be edgedb.Geometry or "+
+		"edgedb.OptionalGeometry got edgedbtypes.Geography")
+
+	err = encoder.Encode(w, types.OptionalGeometry{}, Path("args[0]"), true)
+	assert.EqualError(t, err, "cannot encode edgedb.OptionalGeometry "+
+		"at args[0] because its value is missing")
+
+	decoder, err := BuildDecoderV2(desc, geometryType, Path("out"))
+	require.NoError(t, err)
+
+	var result types.Geometry
+	r := buff.SimpleReader(data)
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	assert.Equal(t, geometry, result)
+
+	decoder, err = BuildDecoderV2(desc, optionalGeometryType, Path("out"))
+	require.NoError(t, err)
+
+	var optional types.OptionalGeometry
+	r = buff.SimpleReader(data)
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&optional)))
+	assert.Equal(t, types.NewOptionalGeometry(geometry), optional)
+
+	decoder.(OptionalDecoder).DecodeMissing(unsafe.Pointer(&optional))
+	assert.Equal(t, types.OptionalGeometry{}, optional)
+
+	_, err = BuildDecoderV2(desc, box2DType, Path("out"))
+	assert.EqualError(t, err, "expected out to be edgedb.Geometry or "+
+		"edgedb.OptionalGeometry got edgedbtypes.Box2D")
+}
+
+// optionalPostGISDecoder writes into the optional PostGIS types
+// through optionalBytesLayout, so their layouts must match.
+func TestOptionalPostGISLayout(t *testing.T) {
+	layout := reflect.TypeOf(optionalBytesLayout{})
+	bytesType := reflect.TypeOf([]byte(nil))
+
+	for _, typ := range []reflect.Type{
+		optionalGeometryType,
+		optionalGeographyType,
+		optionalBox2DType,
+		optionalBox3DType,
+	} {
+		t.Run(typ.Name(), func(t *testing.T) {
+			require.Equal(t, layout.Size(), typ.Size())
+			require.Equal(t, layout.NumField(), typ.NumField())
+
+			val := typ.Field(0)
+			assert.Equal(t, layout.Field(0).Offset, val.Offset)
+			assert.Equal(t, bytesType.Size(), val.Type.Size())
+			require.Equal(t, 1, val.Type.NumField())
+			assert.Equal(t, uintptr(0), val.Type.Field(0).Offset)
+			assert.Equal(t, bytesType, val.Type.Field(0).Type)
+
+			set := typ.Field(1)
+			assert.Equal(t, layout.Field(1).Offset, set.Offset)
+			assert.Equal(t, reflect.Bool, set.Type.Kind())
+		})
+	}
+}
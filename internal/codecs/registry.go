@@ -0,0 +1,274 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/edgedb/edgedb-go/internal/marshal"
+)
+
+// stdScalarNames are the names of the built in scalar types. Protocol
+// versions before 2.0 do not send type names so these are the only names
+// that registered codecs can be matched by on older servers.
+var stdScalarNames = map[types.UUID]string{
+	UUIDID:             "std::uuid",
+	StrID:              "std::str",
+	BytesID:            "std::bytes",
+	Int16ID:            "std::int16",
+	Int32ID:            "std::int32",
+	Int64ID:            "std::int64",
+	Float32ID:          "std::float32",
+	Float64ID:          "std::float64",
+	DecimalID:          "std::decimal",
+	BoolID:             "std::bool",
+	DateTimeID:         "std::datetime",
+	LocalDTID:          "cal::local_datetime",
+	LocalDateID:        "cal::local_date",
+	LocalTimeID:        "cal::local_time",
+	DurationID:         "std::duration",
+	JSONID:             "std::json",
+	BigIntID:           "std::bigint",
+	RelativeDurationID: "cal::relative_duration",
+	DateDurationID:     "cal::date_duration",
+	MemoryID:           "cfg::memory",
+}
+
+var registry = struct {
+	sync.RWMutex
+	codecs map[string]map[reflect.Type]*registeredCodec
+	// types holds every type that has a codec for any scalar type.
+	types map[reflect.Type]bool
+}{
+	codecs: map[string]map[reflect.Type]*registeredCodec{},
+	types:  map[reflect.Type]bool{},
+}
+
+type registeredCodec struct {
+	typ    reflect.Type
+	encode func(interface{}) ([]byte, error)
+	decode func([]byte) (interface{}, error)
+}
+
+// RegisterCodec registers encode and decode as the codec for values of typ
+// in place of the built in codec for the scalar type named name.
+// It panics if name is empty, if typ, encode or decode is nil
+// or if a codec is already registered for name and typ.
+func RegisterCodec(
+	name string,
+	typ reflect.Type,
+	encode func(interface{}) ([]byte, error),
+	decode func([]byte) (interface{}, error),
+) {
+	if name == "" {
+		panic("edgedb: RegisterCodec name must not be empty")
+	}
+
+	if typ == nil || encode == nil || decode == nil {
+		panic("edgedb: RegisterCodec typ, encode and decode must not be nil")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	byType, ok := registry.codecs[name]
+	if !ok {
+		byType = map[reflect.Type]*registeredCodec{}
+		registry.codecs[name] = byType
+	}
+
+	if _, ok := byType[typ]; ok {
+		panic(fmt.Sprintf(
+			"edgedb: RegisterCodec called twice for %v and %v", name, typ))
+	}
+
+	byType[typ] = &registeredCodec{typ, encode, decode}
+	registry.types[typ] = true
+}
+
+// registeredCodecs returns a copy of the codecs registered
+// for the first of names that has any.
+func registeredCodecs(names ...string) map[reflect.Type]*registeredCodec {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, name := range names {
+		byType, ok := registry.codecs[name]
+		if !ok {
+			continue
+		}
+
+		codecs := make(map[reflect.Type]*registeredCodec, len(byType))
+		for typ, codec := range byType {
+			codecs[typ] = codec
+		}
+		return codecs
+	}
+
+	return nil
+}
+
+// scalarNamesV2 returns the name of desc followed by
+// the names of its ancestors from nearest to farthest.
+func scalarNamesV2(desc *descriptor.V2) []string {
+	names := []string{desc.Name}
+	for _, ancestor := range desc.Ancestors {
+		names = append(names, ancestor.Desc.Name)
+	}
+
+	return names
+}
+
+func buildRegisteredDecoder(
+	id types.UUID,
+	typ reflect.Type,
+	names ...string,
+) (Decoder, bool) {
+	codec, ok := registeredCodecs(names...)[typ]
+	if !ok {
+		return nil, false
+	}
+
+	decoder := registeredDecoder{id, codec}
+	if reflect.PointerTo(typ).Implements(optionalUnmarshalerType) {
+		return &optionalRegisteredDecoder{decoder}, true
+	}
+
+	return &decoder, true
+}
+
+type registeredDecoder struct {
+	id    types.UUID
+	codec *registeredCodec
+}
+
+func (c *registeredDecoder) DescriptorID() types.UUID { return c.id }
+
+func (c *registeredDecoder) Decode(r *buff.Reader, out unsafe.Pointer) error {
+	// r.Buf is reused after the message is decoded
+	// so the codec must be given its own copy.
+	data := make([]byte, len(r.Buf))
+	copy(data, r.Buf)
+	r.Discard(len(r.Buf))
+
+	val, err := c.codec.decode(data)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(val)
+	if !v.IsValid() || !v.Type().AssignableTo(c.codec.typ) {
+		return fmt.Errorf("registered decoder for %v returned %T",
+			c.codec.typ, val)
+	}
+
+	reflect.NewAt(c.codec.typ, out).Elem().Set(v)
+	return nil
+}
+
+type optionalRegisteredDecoder struct {
+	registeredDecoder
+}
+
+func (c *optionalRegisteredDecoder) DecodeMissing(out unsafe.Pointer) {
+	val := reflect.NewAt(c.codec.typ, out)
+	method := val.MethodByName("SetMissing")
+	method.Call([]reflect.Value{trueValue})
+}
+
+func (c *optionalRegisteredDecoder) Decode(
+	r *buff.Reader,
+	out unsafe.Pointer,
+) error {
+	if err := c.registeredDecoder.Decode(r, out); err != nil {
+		return err
+	}
+
+	val := reflect.NewAt(c.codec.typ, out)
+	method := val.MethodByName("SetMissing")
+	method.Call([]reflect.Value{falseValue})
+	return nil
+}
+
+// registeredEncoder uses registered codecs for values of a registered type
+// and the built in encoder for all other values. fallback may be nil.
+type registeredEncoder struct {
+	id       types.UUID
+	codecs   map[reflect.Type]*registeredCodec
+	fallback Encoder
+}
+
+func (c *registeredEncoder) DescriptorID() types.UUID { return c.id }
+
+func (c *registeredEncoder) Encode(
+	w *buff.Writer,
+	val interface{},
+	path Path,
+	required bool,
+) error {
+	codec, ok := c.codecs[reflect.TypeOf(val)]
+	if !ok {
+		if c.fallback == nil {
+			return fmt.Errorf("expected %v to be one of %v got %T",
+				path, c.typeNames(), val)
+		}
+
+		return c.fallback.Encode(w, val, path, required)
+	}
+
+	missing := false
+	if in, ok := val.(marshal.OptionalMarshaler); ok {
+		missing = in.Missing()
+	}
+
+	return encodeOptional(w, missing, required,
+		func() error {
+			data, err := codec.encode(val)
+			if err != nil {
+				return err
+			}
+
+			w.PushUint32(uint32(len(data)))
+			w.PushBytes(data)
+			return nil
+		},
+		func() error { return missingValueError(val, path) })
+}
+
+func (c *registeredEncoder) typeNames() []string {
+	names := make([]string, 0, len(c.codecs))
+	for typ := range c.codecs {
+		names = append(names, typ.String())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isRegisteredType returns true if a codec is registered for typ.
+func isRegisteredType(typ reflect.Type) bool {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.types[typ]
+}
@@ -0,0 +1,198 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecs
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/edgedb/edgedb-go/internal"
+	"github.com/edgedb/edgedb-go/internal/buff"
+	"github.com/edgedb/edgedb-go/internal/descriptor"
+	types "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type optionalAddr struct {
+	addr  netip.Addr
+	isSet bool
+}
+
+func (o *optionalAddr) SetMissing(missing bool) { o.isSet = !missing }
+
+func (o optionalAddr) Missing() bool { return !o.isSet }
+
+// rawBytes is registered with a decoder that keeps the data it is given.
+type rawBytes []byte
+
+func init() {
+	RegisterCodec(
+		"test::raw",
+		reflect.TypeOf(rawBytes{}),
+		func(val interface{}) ([]byte, error) { return val.(rawBytes), nil },
+		func(data []byte) (interface{}, error) { return rawBytes(data), nil },
+	)
+
+	RegisterCodec(
+		"test::ip_addr",
+		reflect.TypeOf(netip.Addr{}),
+		func(val interface{}) ([]byte, error) {
+			return []byte(val.(netip.Addr).String()), nil
+		},
+		func(data []byte) (interface{}, error) {
+			return netip.ParseAddr(string(data))
+		},
+	)
+
+	RegisterCodec(
+		"test::ip_addr",
+		reflect.TypeOf(optionalAddr{}),
+		func(val interface{}) ([]byte, error) {
+			return []byte(val.(optionalAddr).addr.String()), nil
+		},
+		func(data []byte) (interface{}, error) {
+			addr, err := netip.ParseAddr(string(data))
+			return optionalAddr{addr: addr}, err
+		},
+	)
+}
+
+func TestRegisteredCodec(t *testing.T) {
+	desc := &descriptor.V2{
+		Type: descriptor.Scalar,
+		ID:   types.UUID{1},
+		Name: "test::ip_addr",
+		Ancestors: []*descriptor.FieldV2{{Desc: descriptor.V2{
+			Type: descriptor.Scalar,
+			ID:   StrID,
+			Name: "std::str",
+		}}},
+	}
+
+	addr := netip.MustParseAddr("10.0.0.1")
+
+	decoder, err := BuildDecoderV2(desc, reflect.TypeOf(addr), Path("out"))
+	require.NoError(t, err)
+
+	var result netip.Addr
+	r := buff.SimpleReader([]byte("10.0.0.1"))
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&result)))
+	assert.Equal(t, addr, result)
+
+	// types that are not registered use the built in codecs
+	decoder, err = BuildDecoderV2(desc, strType, Path("out"))
+	require.NoError(t, err)
+	assert.Equal(t, &StrCodec{StrID}, decoder)
+
+	decoder, err = BuildDecoderV2(
+		desc, reflect.TypeOf(optionalAddr{}), Path("out"))
+	require.NoError(t, err)
+
+	optional := optionalAddr{isSet: true}
+	decoder.(OptionalDecoder).DecodeMissing(unsafe.Pointer(&optional))
+	assert.Equal(t, optionalAddr{}, optional)
+
+	r = buff.SimpleReader([]byte("10.0.0.1"))
+	require.NoError(t, decoder.Decode(r, unsafe.Pointer(&optional)))
+	assert.Equal(t, optionalAddr{addr: addr, isSet: true}, optional)
+
+	r = buff.SimpleReader([]byte("not an address"))
+	err = decoder.Decode(r, unsafe.Pointer(&optional))
+	assert.EqualError(t, err,
+		`ParseAddr("not an address"): unable to parse IP`)
+
+	encoder, err := BuildEncoderV2(desc, internal.ProtocolVersion{Major: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("10.0.0.1"), encodeTestValue(t, encoder, addr))
+	assert.Equal(t, []byte("abc"), encodeTestValue(t, encoder, "abc"))
+
+	w := buff.NewWriter(nil)
+	w.BeginMessage(0)
+	err = encoder.Encode(w, optionalAddr{}, Path("args[0]"), true)
+	assert.EqualError(t, err, "cannot encode codecs.optionalAddr "+
+		"at args[0] because its value is missing")
+}
+
+func TestRegisterCodecTwice(t *testing.T) {
+	encode := func(interface{}) ([]byte, error) { return nil, nil }
+	decode := func([]byte) (interface{}, error) { return nil, nil }
+
+	assert.PanicsWithValue(t,
+		"edgedb: RegisterCodec called twice for test::ip_addr and netip.Addr",
+		func() {
+			RegisterCodec("test::ip_addr", reflect.TypeOf(netip.Addr{}),
+				encode, decode)
+		})
+}
+
+func TestRegisterCodecInvalid(t *testing.T) {
+	encode := func(interface{}) ([]byte, error) { return nil, nil }
+	decode := func([]byte) (interface{}, error) { return nil, nil }
+	typ := reflect.TypeOf(netip.Addr{})
+
+	assert.PanicsWithValue(t,
+		"edgedb: RegisterCodec name must not be empty",
+		func() { RegisterCodec("", typ, encode, decode) })
+
+	msg := "edgedb: RegisterCodec typ, encode and decode must not be nil"
+	assert.PanicsWithValue(t, msg,
+		func() { RegisterCodec("test::invalid", nil, encode, decode) })
+	assert.PanicsWithValue(t, msg,
+		func() { RegisterCodec("test::invalid", typ, nil, decode) })
+	assert.PanicsWithValue(t, msg,
+		func() { RegisterCodec("test::invalid", typ, encode, nil) })
+}
+
+func TestIsRegisteredType(t *testing.T) {
+	assert.True(t, isRegisteredType(reflect.TypeOf(netip.Addr{})))
+	assert.True(t, isRegisteredType(reflect.TypeOf(optionalAddr{})))
+	assert.False(t, isRegisteredType(reflect.TypeOf(&netip.Addr{})))
+	assert.False(t, isRegisteredType(reflect.TypeOf("")))
+}
+
+func TestRegisteredDecoderCopiesData(t *testing.T) {
+	desc := &descriptor.V2{
+		Type: descriptor.Scalar,
+		ID:   types.UUID{2},
+		Name: "test::raw",
+		Ancestors: []*descriptor.FieldV2{{Desc: descriptor.V2{
+			Type: descriptor.Scalar,
+			ID:   BytesID,
+			Name: "std::bytes",
+		}}},
+	}
+
+	decoder, err := BuildDecoderV2(desc, reflect.TypeOf(rawBytes{}), "out")
+	require.NoError(t, err)
+
+	// The same buffer is reused for each message.
+	buf := []byte("abc")
+	var first rawBytes
+	require.NoError(t, decoder.Decode(
+		buff.SimpleReader(buf), unsafe.Pointer(&first)))
+
+	copy(buf, "xyz")
+	var second rawBytes
+	require.NoError(t, decoder.Decode(
+		buff.SimpleReader(buf), unsafe.Pointer(&second)))
+
+	assert.Equal(t, rawBytes("abc"), first)
+	assert.Equal(t, rawBytes("xyz"), second)
+}
@@ -25,6 +25,13 @@ import (
 	"github.com/edgedb/edgedb-go/internal/edgedbtypes"
 )
 
+// Object shape element flags
+// https://www.edgedb.com/docs/internals/protocol/typedesc
+const (
+	fieldImplicit     uint32 = 1 << 0
+	fieldLinkProperty uint32 = 1 << 1
+)
+
 // V2 is a type descriptor
 // https://www.edgedb.com/docs/internals/protocol/typedesc
 type V2 struct {
@@ -38,10 +45,12 @@ type V2 struct {
 
 // FieldV2 represents the child of a descriptor
 type FieldV2 struct {
-	Name     string
-	Desc     V2
-	Required bool
-	Union    bool
+	Name         string
+	Desc         V2
+	Required     bool
+	Union        bool
+	Implicit     bool
+	LinkProperty bool
 }
 
 // PopV2 builds a descriptor tree from a describe statement type description.
@@ -242,7 +251,7 @@ func objectFields2pX(r *buff.Reader, descriptors []V2, input bool) (
 
 	for i := 0; i < n; i++ {
 		var required bool
-		r.Discard(4) // flags
+		flags := r.PopUint32()
 		card := r.PopUint8()
 		switch card {
 		case 0x6f, 0x6d:
@@ -253,9 +262,11 @@ func objectFields2pX(r *buff.Reader, descriptors []V2, input bool) (
 			return nil, fmt.Errorf("unexpected cardinality: %v", card)
 		}
 		fields[i] = &FieldV2{
-			Name:     r.PopString(),
-			Desc:     descriptors[r.PopUint16()],
-			Required: required,
+			Name:         r.PopString(),
+			Desc:         descriptors[r.PopUint16()],
+			Required:     required,
+			Implicit:     flags&fieldImplicit != 0,
+			LinkProperty: flags&fieldLinkProperty != 0,
 		}
 		if !input {
 			r.PopUint16() // source_type
@@ -16,6 +16,8 @@
 
 package edgedbtypes
 
+import "time"
+
 // MultiRangeInt32 is a type alias for a slice of RangeInt32 values.
 type MultiRangeInt32 = []RangeInt32
 
@@ -38,3 +40,124 @@ type MultiRangeLocalDateTime = []RangeLocalDateTime
 // MultiRangeLocalDate is a type alias for a slice of
 // RangeLocalDate values.
 type MultiRangeLocalDate = []RangeLocalDate
+
+// NormalizeMultiRangeInt32 returns the ranges in m sorted with overlapping
+// and adjacent ranges merged and empty ranges removed.
+func NormalizeMultiRangeInt32(m MultiRangeInt32) MultiRangeInt32 {
+	spans := make([]span[int32], len(m))
+	for i, r := range m {
+		spans[i] = r.span()
+	}
+
+	spans = rangeInt32Ops.normalize(spans)
+	out := make(MultiRangeInt32, len(spans))
+	for i, s := range spans {
+		out[i] = rangeInt32FromSpan(s)
+	}
+
+	return out
+}
+
+// NormalizeMultiRangeInt64 returns the ranges in m sorted with overlapping
+// and adjacent ranges merged and empty ranges removed.
+func NormalizeMultiRangeInt64(m MultiRangeInt64) MultiRangeInt64 {
+	spans := make([]span[int64], len(m))
+	for i, r := range m {
+		spans[i] = r.span()
+	}
+
+	spans = rangeInt64Ops.normalize(spans)
+	out := make(MultiRangeInt64, len(spans))
+	for i, s := range spans {
+		out[i] = rangeInt64FromSpan(s)
+	}
+
+	return out
+}
+
+// NormalizeMultiRangeFloat32 returns the ranges in m sorted with overlapping
+// and adjacent ranges merged and empty ranges removed.
+func NormalizeMultiRangeFloat32(m MultiRangeFloat32) MultiRangeFloat32 {
+	spans := make([]span[float32], len(m))
+	for i, r := range m {
+		spans[i] = r.span()
+	}
+
+	spans = rangeFloat32Ops.normalize(spans)
+	out := make(MultiRangeFloat32, len(spans))
+	for i, s := range spans {
+		out[i] = rangeFloat32FromSpan(s)
+	}
+
+	return out
+}
+
+// NormalizeMultiRangeFloat64 returns the ranges in m sorted with overlapping
+// and adjacent ranges merged and empty ranges removed.
+func NormalizeMultiRangeFloat64(m MultiRangeFloat64) MultiRangeFloat64 {
+	spans := make([]span[float64], len(m))
+	for i, r := range m {
+		spans[i] = r.span()
+	}
+
+	spans = rangeFloat64Ops.normalize(spans)
+	out := make(MultiRangeFloat64, len(spans))
+	for i, s := range spans {
+		out[i] = rangeFloat64FromSpan(s)
+	}
+
+	return out
+}
+
+// NormalizeMultiRangeDateTime returns the ranges in m sorted with overlapping
+// and adjacent ranges merged and empty ranges removed.
+func NormalizeMultiRangeDateTime(m MultiRangeDateTime) MultiRangeDateTime {
+	spans := make([]span[time.Time], len(m))
+	for i, r := range m {
+		spans[i] = r.span()
+	}
+
+	spans = rangeDateTimeOps.normalize(spans)
+	out := make(MultiRangeDateTime, len(spans))
+	for i, s := range spans {
+		out[i] = rangeDateTimeFromSpan(s)
+	}
+
+	return out
+}
+
+// NormalizeMultiRangeLocalDateTime returns the ranges in m sorted with
+// overlapping and adjacent ranges merged and empty ranges removed.
+func NormalizeMultiRangeLocalDateTime(
+	m MultiRangeLocalDateTime,
+) MultiRangeLocalDateTime {
+	spans := make([]span[LocalDateTime], len(m))
+	for i, r := range m {
+		spans[i] = r.span()
+	}
+
+	spans = rangeLocalDateTimeOps.normalize(spans)
+	out := make(MultiRangeLocalDateTime, len(spans))
+	for i, s := range spans {
+		out[i] = rangeLocalDateTimeFromSpan(s)
+	}
+
+	return out
+}
+
+// NormalizeMultiRangeLocalDate returns the ranges in m sorted with overlapping
+// and adjacent ranges merged and empty ranges removed.
+func NormalizeMultiRangeLocalDate(m MultiRangeLocalDate) MultiRangeLocalDate {
+	spans := make([]span[LocalDate], len(m))
+	for i, r := range m {
+		spans[i] = r.span()
+	}
+
+	spans = rangeLocalDateOps.normalize(spans)
+	out := make(MultiRangeLocalDate, len(spans))
+	for i, s := range spans {
+		out[i] = rangeLocalDateFromSpan(s)
+	}
+
+	return out
+}
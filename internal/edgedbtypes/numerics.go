@@ -18,7 +18,13 @@ package edgedbtypes
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/edgedb/edgedb-go/internal"
 )
 
 // NewOptionalBigInt is a convenience function for creating an OptionalBigInt
@@ -78,3 +84,291 @@ func (o *OptionalBigInt) UnmarshalJSON(bytes []byte) error {
 
 	return nil
 }
+
+var (
+	bigOne  = big.NewInt(1)
+	bigTwo  = big.NewInt(2)
+	bigFive = big.NewInt(5)
+)
+
+// NewDecimal returns a Decimal with the value unscaled * 10**-scale.
+// A negative scale multiplies unscaled by a power of ten.
+func NewDecimal(unscaled *big.Int, scale int) Decimal {
+	coef := new(big.Int)
+	if unscaled != nil {
+		coef.Set(unscaled)
+	}
+
+	if scale < 0 {
+		coef.Mul(coef, internal.Pow10(-scale))
+		scale = 0
+	}
+
+	return Decimal{coef: coef, scale: scale}
+}
+
+// NewDecimalFromInt64 returns a Decimal with the value i.
+func NewDecimalFromInt64(i int64) Decimal {
+	return Decimal{coef: big.NewInt(i)}
+}
+
+// NewDecimalFromFloat64 returns the Decimal with the fewest digits
+// that converts back to f. It returns an error if f is NaN or infinite.
+func NewDecimalFromFloat64(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("cannot convert %v to Decimal", f)
+	}
+
+	return ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// NewDecimalFromRat returns a Decimal with the value r.
+// It returns an error if r can not be written
+// with a finite number of decimal digits, for example 1/3.
+func NewDecimalFromRat(r *big.Rat) (Decimal, error) {
+	// r is a finite decimal if its denominator
+	// has no prime factors other than 2 and 5.
+	denom := new(big.Int).Set(r.Denom())
+	rem := new(big.Int)
+	twos, fives := 0, 0
+	for {
+		q, m := new(big.Int).QuoRem(denom, bigTwo, rem)
+		if m.Sign() != 0 {
+			break
+		}
+		denom = q
+		twos++
+	}
+	for {
+		q, m := new(big.Int).QuoRem(denom, bigFive, rem)
+		if m.Sign() != 0 {
+			break
+		}
+		denom = q
+		fives++
+	}
+
+	if denom.Cmp(bigOne) != 0 {
+		return Decimal{}, fmt.Errorf(
+			"cannot convert %v to Decimal exactly", r.RatString())
+	}
+
+	scale := twos
+	if fives > scale {
+		scale = fives
+	}
+	coef := new(big.Int).Mul(r.Num(), internal.Pow10(scale))
+	coef.Quo(coef, r.Denom())
+	return Decimal{coef: coef, scale: scale}, nil
+}
+
+// ParseDecimal parses a decimal number like -12.340 or 1.5e3.
+// The scale of the result is the number of digits after the decimal point.
+func ParseDecimal(s string) (Decimal, error) {
+	d := Decimal{}
+	if err := d.UnmarshalText([]byte(s)); err != nil {
+		return Decimal{}, err
+	}
+
+	return d, nil
+}
+
+// Decimal is an exact arbitrary precision decimal number.
+// It is represented as an unscaled integer and a scale, the number of
+// digits after the decimal point. The zero value is 0.
+//
+// Decimals are immutable, methods never change their receiver.
+type Decimal struct {
+	coef  *big.Int
+	scale int
+}
+
+// Unscaled returns the decimal's digits as an integer,
+// the decimal's value is Unscaled() * 10**-Scale().
+func (d Decimal) Unscaled() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(d.coef)
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int { return d.scale }
+
+// Sign returns -1, 0 or 1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	if d.coef == nil {
+		return 0
+	}
+
+	return d.coef.Sign()
+}
+
+// Rat returns d as a *big.Rat.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.Unscaled(), internal.Pow10(d.scale))
+}
+
+// Float64 returns the float64 value nearest to d
+// and a boolean indicating whether the conversion is exact.
+func (d Decimal) Float64() (float64, bool) {
+	return d.Rat().Float64()
+}
+
+// Cmp compares d and other and returns -1 if d < other,
+// 0 if d == other and 1 if d > other. The scale is ignored,
+// so 1.0 and 1.00 are equal.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+// String returns d formatted with Scale() digits after the decimal point.
+func (d Decimal) String() string {
+	digits := d.Unscaled()
+	sign := ""
+	if digits.Sign() < 0 {
+		sign = "-"
+		digits.Neg(digits)
+	}
+
+	if d.scale == 0 {
+		return sign + digits.String()
+	}
+
+	str := digits.String()
+	if len(str) <= d.scale {
+		str = strings.Repeat("0", d.scale-len(str)+1) + str
+	}
+
+	point := len(str) - d.scale
+	return sign + str[:point] + "." + str[point:]
+}
+
+// MarshalText returns d marshaled as text.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// maxDecimalScale is the largest scale the decimal wire format allows.
+const maxDecimalScale = math.MaxUint16
+
+// UnmarshalText unmarshals bytes into *d.
+// The resulting scale must be between -65535 and 65535.
+func (d *Decimal) UnmarshalText(b []byte) error {
+	s := string(b)
+	invalid := fmt.Errorf("invalid decimal: %q", s)
+
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return invalid
+		}
+		exp = e
+		s = s[:i]
+	}
+
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign = s[:1]
+		s = s[1:]
+	}
+
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+
+	digits := whole + frac
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return invalid
+	}
+
+	// Limit the scale to what can be sent to the server. This also keeps
+	// inputs like 1e999999999 from allocating huge numbers.
+	if exp < len(frac)-maxDecimalScale || exp > len(frac)+maxDecimalScale {
+		return fmt.Errorf("decimal exponent out of range: %q", string(b))
+	}
+
+	coef, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return invalid
+	}
+
+	*d = NewDecimal(coef, len(frac)-exp)
+	return nil
+}
+
+// MarshalJSON returns d marshaled as a json string.
+// Strings are used so that no precision is lost by json decoders
+// that decode numbers as floats.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON unmarshals a json string or number into *d.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		b = []byte(s)
+	}
+
+	return d.UnmarshalText(b)
+}
+
+// NewOptionalDecimal is a convenience function for creating an
+// OptionalDecimal with its value set to v.
+func NewOptionalDecimal(v Decimal) OptionalDecimal {
+	o := OptionalDecimal{}
+	o.Set(v)
+	return o
+}
+
+// OptionalDecimal is an optional Decimal. Optional types must be used for
+// out parameters when a shape field is not required.
+type OptionalDecimal struct {
+	val   Decimal
+	isSet bool
+}
+
+// Get returns the value and a boolean indicating if the value is present.
+func (o OptionalDecimal) Get() (Decimal, bool) { return o.val, o.isSet }
+
+// Set sets the value.
+func (o *OptionalDecimal) Set(val Decimal) {
+	o.val = val
+	o.isSet = true
+}
+
+// Unset marks the value as missing.
+func (o *OptionalDecimal) Unset() {
+	o.val = Decimal{}
+	o.isSet = false
+}
+
+// MarshalJSON returns o marshaled as json.
+func (o OptionalDecimal) MarshalJSON() ([]byte, error) {
+	if o.isSet {
+		return o.val.MarshalJSON()
+	}
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON unmarshals bytes into *o.
+func (o *OptionalDecimal) UnmarshalJSON(bytes []byte) error {
+	if bytes[0] == 0x6e { // null
+		o.Unset()
+		return nil
+	}
+
+	if err := o.val.UnmarshalJSON(bytes); err != nil {
+		return err
+	}
+	o.isSet = true
+
+	return nil
+}
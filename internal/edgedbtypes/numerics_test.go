@@ -18,6 +18,8 @@ package edgedbtypes
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"math/big"
 	"testing"
 
@@ -66,3 +68,129 @@ func TestUnmarshalOptionalBigInt(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDecimal(t *testing.T) {
+	cases := []struct {
+		input    string
+		unscaled int64
+		scale    int
+		str      string
+	}{
+		{"0", 0, 0, "0"},
+		{"-0.00", 0, 2, "0.00"},
+		{"12.340", 12340, 3, "12.340"},
+		{"+12.340", 12340, 3, "12.340"},
+		{"-0.05", -5, 2, "-0.05"},
+		{".5", 5, 1, "0.5"},
+		{"5.", 5, 0, "5"},
+		{"1.5e3", 1500, 0, "1500"},
+		{"1.5E-3", 15, 4, "0.0015"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			d, err := ParseDecimal(c.input)
+			require.NoError(t, err)
+			assert.Equal(t, big.NewInt(c.unscaled), d.Unscaled())
+			assert.Equal(t, c.scale, d.Scale())
+			assert.Equal(t, c.str, d.String())
+		})
+	}
+
+	invalid := []string{"", "-", ".", "1.2.3", "1e", "abc", "1_000"}
+	for _, input := range invalid {
+		_, err := ParseDecimal(input)
+		assert.EqualError(t, err, fmt.Sprintf("invalid decimal: %q", input))
+	}
+
+	d, err := ParseDecimal("1e-65535")
+	require.NoError(t, err)
+	assert.Equal(t, 65535, d.Scale())
+
+	d, err = ParseDecimal("0.5e-65534")
+	require.NoError(t, err)
+	assert.Equal(t, 65535, d.Scale())
+
+	d, err = ParseDecimal("1e65535")
+	require.NoError(t, err)
+	assert.Equal(t, 0, d.Scale())
+	assert.Len(t, d.String(), 65536)
+
+	outOfRange := []string{
+		"1e999999999",
+		"-1e-999999999",
+		"1e65536",
+		"1e-65536",
+		"0.5e-65535",
+		"1e9223372036854775807",
+	}
+	for _, input := range outOfRange {
+		_, err := ParseDecimal(input)
+		assert.EqualError(t, err,
+			fmt.Sprintf("decimal exponent out of range: %q", input))
+	}
+}
+
+func TestDecimalConversions(t *testing.T) {
+	d := NewDecimal(big.NewInt(-125), 2)
+	assert.Equal(t, "-1.25", d.String())
+	assert.Equal(t, big.NewRat(-5, 4), d.Rat())
+
+	f, exact := d.Float64()
+	assert.Equal(t, -1.25, f)
+	assert.True(t, exact)
+
+	assert.Equal(t, "1200", NewDecimal(big.NewInt(12), -2).String())
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.Equal(t, "42", NewDecimalFromInt64(42).String())
+
+	d, err := NewDecimalFromFloat64(0.1)
+	require.NoError(t, err)
+	assert.Equal(t, "0.1", d.String())
+
+	_, err = NewDecimalFromFloat64(math.Inf(1))
+	assert.EqualError(t, err, "cannot convert +Inf to Decimal")
+
+	d, err = NewDecimalFromRat(big.NewRat(3, 8))
+	require.NoError(t, err)
+	assert.Equal(t, "0.375", d.String())
+
+	_, err = NewDecimalFromRat(big.NewRat(1, 3))
+	assert.EqualError(t, err, "cannot convert 1/3 to Decimal exactly")
+
+	a, err := ParseDecimal("1.0")
+	require.NoError(t, err)
+	b, err := ParseDecimal("1.00")
+	require.NoError(t, err)
+	assert.Equal(t, 0, a.Cmp(b))
+	assert.Equal(t, -1, a.Cmp(NewDecimalFromInt64(2)))
+}
+
+func TestDecimalJSON(t *testing.T) {
+	d, err := ParseDecimal("12.340")
+	require.NoError(t, err)
+
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, `"12.340"`, string(b))
+
+	var fromString, fromNumber Decimal
+	require.NoError(t, json.Unmarshal([]byte(`"12.340"`), &fromString))
+	require.NoError(t, json.Unmarshal([]byte(`12.340`), &fromNumber))
+	assert.Equal(t, d, fromString)
+	assert.Equal(t, d, fromNumber)
+
+	b, err = json.Marshal(OptionalDecimal{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(b))
+
+	var o OptionalDecimal
+	require.NoError(t, json.Unmarshal([]byte(`"1.5"`), &o))
+	val, ok := o.Get()
+	assert.True(t, ok)
+	assert.Equal(t, "1.5", val.String())
+
+	require.NoError(t, json.Unmarshal([]byte(`null`), &o))
+	_, ok = o.Get()
+	assert.False(t, ok)
+}
@@ -0,0 +1,65 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+// Object is a dynamically decoded object or named tuple.
+// It is used when the shape of a query's result is not known ahead of time.
+// Fields are kept in the order the server returned them.
+type Object struct {
+	Fields []ObjectField
+}
+
+// ObjectField is a field of an Object.
+type ObjectField struct {
+	// Name is the field's name. Link property names start with @.
+	Name string
+
+	// Value is nil if the field is missing.
+	Value interface{}
+
+	// Implicit is true if the server added the field to the shape
+	// without it being requested, for example id.
+	Implicit bool
+
+	// LinkProperty is true if the field is a link property.
+	LinkProperty bool
+}
+
+// Get returns the value of the field with the given name.
+// The returned bool is false if o has no such field.
+func (o Object) Get(name string) (interface{}, bool) {
+	for _, field := range o.Fields {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Map returns o's explicit fields keyed by name.
+// Implicit fields are omitted.
+func (o Object) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(o.Fields))
+	for _, field := range o.Fields {
+		if !field.Implicit {
+			m[field.Name] = field.Value
+		}
+	}
+
+	return m
+}
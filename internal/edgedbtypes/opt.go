@@ -0,0 +1,74 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import "encoding/json"
+
+// NewOpt is a convenience function for creating an Opt with its value set
+// to v.
+func NewOpt[T any](v T) Opt[T] {
+	o := Opt[T]{}
+	o.Set(v)
+	return o
+}
+
+// Opt is an optional T. It can be used in place of the other optional types
+// and for any T that can be decoded, including structs and types with custom
+// unmarshalers.
+type Opt[T any] struct {
+	val   T
+	isSet bool
+}
+
+// Get returns the value and a boolean indicating if the value is present.
+func (o Opt[T]) Get() (T, bool) { return o.val, o.isSet }
+
+// Set sets the value.
+func (o *Opt[T]) Set(val T) {
+	o.val = val
+	o.isSet = true
+}
+
+// Unset marks the value as missing.
+func (o *Opt[T]) Unset() {
+	var zero T
+	o.val = zero
+	o.isSet = false
+}
+
+// MarshalJSON returns o marshaled as json.
+func (o Opt[T]) MarshalJSON() ([]byte, error) {
+	if o.isSet {
+		return json.Marshal(o.val)
+	}
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON unmarshals bytes into *o.
+func (o *Opt[T]) UnmarshalJSON(bytes []byte) error {
+	if bytes[0] == 0x6e { // null
+		o.Unset()
+		return nil
+	}
+
+	if err := json.Unmarshal(bytes, &o.val); err != nil {
+		return err
+	}
+	o.isSet = true
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalOpt(t *testing.T) {
+	type point struct {
+		X int `json:"x"`
+	}
+
+	cases := []struct {
+		input    interface{}
+		expected string
+	}{
+		{Opt[string]{}, "null"},
+		{NewOpt("text"), `"text"`},
+		{NewOpt(int64(0)), "0"},
+		{NewOpt(point{1}), `{"x":1}`},
+		{NewOpt(NewOpt(true)), "true"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.expected, func(t *testing.T) {
+			b, err := json.Marshal(c.input)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, string(b))
+		})
+	}
+}
+
+func TestUnmarshalOpt(t *testing.T) {
+	var str Opt[string]
+	require.NoError(t, json.Unmarshal([]byte(`"text"`), &str))
+	assert.Equal(t, NewOpt("text"), str)
+
+	require.NoError(t, json.Unmarshal([]byte("null"), &str))
+	assert.Equal(t, Opt[string]{}, str)
+
+	var num Opt[int64]
+	err := json.Unmarshal([]byte(`"text"`), &num)
+	assert.EqualError(t, err,
+		"json: cannot unmarshal string into Go value of type int64")
+	assert.Equal(t, Opt[int64]{}, num)
+}
+
+func TestOptSetAndUnset(t *testing.T) {
+	var o Opt[[]byte]
+	val, ok := o.Get()
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	o.Set([]byte{})
+	val, ok = o.Get()
+	assert.True(t, ok)
+	assert.Equal(t, []byte{}, val)
+
+	o.Unset()
+	assert.Equal(t, Opt[[]byte]{}, o)
+}
@@ -0,0 +1,424 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PostGIS values are carried as well-known binary (WKB) as produced and
+// accepted by the PostGIS extension. The helpers in this file only
+// understand two dimensional points, line strings and polygons.
+
+// NewGeometry returns the Geometry for shape.
+func NewGeometry(shape Shape) Geometry {
+	return Geometry{wkb: shape.appendWKB(nil)}
+}
+
+// NewGeometryFromWKB returns a Geometry holding a copy of wkb.
+// wkb is not validated.
+func NewGeometryFromWKB(wkb []byte) Geometry {
+	return Geometry{wkb: append([]byte(nil), wkb...)}
+}
+
+// ParseGeometry parses a POINT, LINESTRING or POLYGON from well-known text.
+func ParseGeometry(wkt string) (Geometry, error) {
+	shape, err := parseWKT(wkt)
+	if err != nil {
+		return Geometry{}, err
+	}
+
+	return NewGeometry(shape), nil
+}
+
+// Geometry is an ext::postgis::geometry value.
+type Geometry struct {
+	wkb []byte
+}
+
+// WKB returns a copy of the well-known binary for g.
+func (g Geometry) WKB() []byte { return append([]byte(nil), g.wkb...) }
+
+// Shape decodes g into a Point, LineString or Polygon.
+func (g Geometry) Shape() (Shape, error) { return decodeWKB(g.wkb) }
+
+// WKT returns g as well-known text.
+func (g Geometry) WKT() (string, error) {
+	shape, err := decodeWKB(g.wkb)
+	if err != nil {
+		return "", err
+	}
+
+	return string(shape.appendWKT(nil)), nil
+}
+
+// MarshalJSON returns g marshaled as a json string
+// holding the hex encoded well-known binary.
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	return marshalWKBJSON(g.wkb)
+}
+
+// UnmarshalJSON unmarshals a json string
+// holding hex encoded well-known binary into *g.
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	return unmarshalWKBJSON(data, &g.wkb)
+}
+
+// NewOptionalGeometry is a convenience function for creating an
+// OptionalGeometry with its value set to v.
+func NewOptionalGeometry(v Geometry) OptionalGeometry {
+	o := OptionalGeometry{}
+	o.Set(v)
+	return o
+}
+
+// OptionalGeometry is an optional Geometry. Optional types must be used for
+// out parameters when a shape field is not required.
+type OptionalGeometry struct {
+	val   Geometry
+	isSet bool
+}
+
+// Get returns the value and a boolean indicating if the value is present.
+func (o OptionalGeometry) Get() (Geometry, bool) { return o.val, o.isSet }
+
+// Set sets the value.
+func (o *OptionalGeometry) Set(val Geometry) {
+	o.val = val
+	o.isSet = true
+}
+
+// Unset marks the value as missing.
+func (o *OptionalGeometry) Unset() {
+	o.val = Geometry{}
+	o.isSet = false
+}
+
+// MarshalJSON returns o marshaled as json.
+func (o OptionalGeometry) MarshalJSON() ([]byte, error) {
+	if o.isSet {
+		return json.Marshal(o.val)
+	}
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON unmarshals bytes into *o.
+func (o *OptionalGeometry) UnmarshalJSON(bytes []byte) error {
+	if bytes[0] == 0x6e { // null
+		o.Unset()
+		return nil
+	}
+
+	if err := json.Unmarshal(bytes, &o.val); err != nil {
+		return err
+	}
+	o.isSet = true
+
+	return nil
+}
+
+// NewGeography returns the Geography for shape.
+func NewGeography(shape Shape) Geography {
+	return Geography{wkb: shape.appendWKB(nil)}
+}
+
+// NewGeographyFromWKB returns a Geography holding a copy of wkb.
+// wkb is not validated.
+func NewGeographyFromWKB(wkb []byte) Geography {
+	return Geography{wkb: append([]byte(nil), wkb...)}
+}
+
+// ParseGeography parses a POINT, LINESTRING or POLYGON from well-known text.
+func ParseGeography(wkt string) (Geography, error) {
+	shape, err := parseWKT(wkt)
+	if err != nil {
+		return Geography{}, err
+	}
+
+	return NewGeography(shape), nil
+}
+
+// Geography is an ext::postgis::geography value.
+type Geography struct {
+	wkb []byte
+}
+
+// WKB returns a copy of the well-known binary for g.
+func (g Geography) WKB() []byte { return append([]byte(nil), g.wkb...) }
+
+// Shape decodes g into a Point, LineString or Polygon.
+func (g Geography) Shape() (Shape, error) { return decodeWKB(g.wkb) }
+
+// WKT returns g as well-known text.
+func (g Geography) WKT() (string, error) {
+	shape, err := decodeWKB(g.wkb)
+	if err != nil {
+		return "", err
+	}
+
+	return string(shape.appendWKT(nil)), nil
+}
+
+// MarshalJSON returns g marshaled as a json string
+// holding the hex encoded well-known binary.
+func (g Geography) MarshalJSON() ([]byte, error) {
+	return marshalWKBJSON(g.wkb)
+}
+
+// UnmarshalJSON unmarshals a json string
+// holding hex encoded well-known binary into *g.
+func (g *Geography) UnmarshalJSON(data []byte) error {
+	return unmarshalWKBJSON(data, &g.wkb)
+}
+
+// NewOptionalGeography is a convenience function for creating an
+// OptionalGeography with its value set to v.
+func NewOptionalGeography(v Geography) OptionalGeography {
+	o := OptionalGeography{}
+	o.Set(v)
+	return o
+}
+
+// OptionalGeography is an optional Geography. Optional types must be used for
+// out parameters when a shape field is not required.
+type OptionalGeography struct {
+	val   Geography
+	isSet bool
+}
+
+// Get returns the value and a boolean indicating if the value is present.
+func (o OptionalGeography) Get() (Geography, bool) { return o.val, o.isSet }
+
+// Set sets the value.
+func (o *OptionalGeography) Set(val Geography) {
+	o.val = val
+	o.isSet = true
+}
+
+// Unset marks the value as missing.
+func (o *OptionalGeography) Unset() {
+	o.val = Geography{}
+	o.isSet = false
+}
+
+// MarshalJSON returns o marshaled as json.
+func (o OptionalGeography) MarshalJSON() ([]byte, error) {
+	if o.isSet {
+		return json.Marshal(o.val)
+	}
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON unmarshals bytes into *o.
+func (o *OptionalGeography) UnmarshalJSON(bytes []byte) error {
+	if bytes[0] == 0x6e { // null
+		o.Unset()
+		return nil
+	}
+
+	if err := json.Unmarshal(bytes, &o.val); err != nil {
+		return err
+	}
+	o.isSet = true
+
+	return nil
+}
+
+// NewBox2DFromWKB returns a Box2D holding a copy of wkb.
+// wkb is not validated.
+func NewBox2DFromWKB(wkb []byte) Box2D {
+	return Box2D{wkb: append([]byte(nil), wkb...)}
+}
+
+// Box2D is an ext::postgis::box2d value. The server sends boxes
+// as the well-known binary of the equivalent geometry.
+type Box2D struct {
+	wkb []byte
+}
+
+// WKB returns a copy of the well-known binary for b.
+func (b Box2D) WKB() []byte { return append([]byte(nil), b.wkb...) }
+
+// MarshalJSON returns b marshaled as a json string
+// holding the hex encoded well-known binary.
+func (b Box2D) MarshalJSON() ([]byte, error) {
+	return marshalWKBJSON(b.wkb)
+}
+
+// UnmarshalJSON unmarshals a json string
+// holding hex encoded well-known binary into *b.
+func (b *Box2D) UnmarshalJSON(data []byte) error {
+	return unmarshalWKBJSON(data, &b.wkb)
+}
+
+// NewOptionalBox2D is a convenience function for creating an
+// OptionalBox2D with its value set to v.
+func NewOptionalBox2D(v Box2D) OptionalBox2D {
+	o := OptionalBox2D{}
+	o.Set(v)
+	return o
+}
+
+// OptionalBox2D is an optional Box2D. Optional types must be used for out
+// parameters when a shape field is not required.
+type OptionalBox2D struct {
+	val   Box2D
+	isSet bool
+}
+
+// Get returns the value and a boolean indicating if the value is present.
+func (o OptionalBox2D) Get() (Box2D, bool) { return o.val, o.isSet }
+
+// Set sets the value.
+func (o *OptionalBox2D) Set(val Box2D) {
+	o.val = val
+	o.isSet = true
+}
+
+// Unset marks the value as missing.
+func (o *OptionalBox2D) Unset() {
+	o.val = Box2D{}
+	o.isSet = false
+}
+
+// MarshalJSON returns o marshaled as json.
+func (o OptionalBox2D) MarshalJSON() ([]byte, error) {
+	if o.isSet {
+		return json.Marshal(o.val)
+	}
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON unmarshals bytes into *o.
+func (o *OptionalBox2D) UnmarshalJSON(bytes []byte) error {
+	if bytes[0] == 0x6e { // null
+		o.Unset()
+		return nil
+	}
+
+	if err := json.Unmarshal(bytes, &o.val); err != nil {
+		return err
+	}
+	o.isSet = true
+
+	return nil
+}
+
+// NewBox3DFromWKB returns a Box3D holding a copy of wkb.
+// wkb is not validated.
+func NewBox3DFromWKB(wkb []byte) Box3D {
+	return Box3D{wkb: append([]byte(nil), wkb...)}
+}
+
+// Box3D is an ext::postgis::box3d value. The server sends boxes
+// as the well-known binary of the equivalent geometry.
+type Box3D struct {
+	wkb []byte
+}
+
+// WKB returns a copy of the well-known binary for b.
+func (b Box3D) WKB() []byte { return append([]byte(nil), b.wkb...) }
+
+// MarshalJSON returns b marshaled as a json string
+// holding the hex encoded well-known binary.
+func (b Box3D) MarshalJSON() ([]byte, error) {
+	return marshalWKBJSON(b.wkb)
+}
+
+// UnmarshalJSON unmarshals a json string
+// holding hex encoded well-known binary into *b.
+func (b *Box3D) UnmarshalJSON(data []byte) error {
+	return unmarshalWKBJSON(data, &b.wkb)
+}
+
+// NewOptionalBox3D is a convenience function for creating an
+// OptionalBox3D with its value set to v.
+func NewOptionalBox3D(v Box3D) OptionalBox3D {
+	o := OptionalBox3D{}
+	o.Set(v)
+	return o
+}
+
+// OptionalBox3D is an optional Box3D. Optional types must be used for out
+// parameters when a shape field is not required.
+type OptionalBox3D struct {
+	val   Box3D
+	isSet bool
+}
+
+// Get returns the value and a boolean indicating if the value is present.
+func (o OptionalBox3D) Get() (Box3D, bool) { return o.val, o.isSet }
+
+// Set sets the value.
+func (o *OptionalBox3D) Set(val Box3D) {
+	o.val = val
+	o.isSet = true
+}
+
+// Unset marks the value as missing.
+func (o *OptionalBox3D) Unset() {
+	o.val = Box3D{}
+	o.isSet = false
+}
+
+// MarshalJSON returns o marshaled as json.
+func (o OptionalBox3D) MarshalJSON() ([]byte, error) {
+	if o.isSet {
+		return json.Marshal(o.val)
+	}
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON unmarshals bytes into *o.
+func (o *OptionalBox3D) UnmarshalJSON(bytes []byte) error {
+	if bytes[0] == 0x6e { // null
+		o.Unset()
+		return nil
+	}
+
+	if err := json.Unmarshal(bytes, &o.val); err != nil {
+		return err
+	}
+	o.isSet = true
+
+	return nil
+}
+
+// marshalWKBJSON returns wkb as a json string of upper case hex digits,
+// the same text format PostGIS uses.
+func marshalWKBJSON(wkb []byte) ([]byte, error) {
+	return json.Marshal(strings.ToUpper(hex.EncodeToString(wkb)))
+}
+
+// unmarshalWKBJSON decodes a json string of hex digits into *wkb.
+func unmarshalWKBJSON(data []byte, wkb *[]byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("invalid well-known binary: %w", err)
+	}
+
+	*wkb = decoded
+	return nil
+}
@@ -0,0 +1,205 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeometryWKT(t *testing.T) {
+	samples := []struct {
+		input  string
+		output string
+		shape  Shape
+	}{
+		{"POINT(1 2)", "POINT(1 2)", Point{1, 2}},
+		{" point ( -1.5  2e3 ) ", "POINT(-1.5 2000)", Point{-1.5, 2000}},
+		{
+			"LINESTRING(0 0, 1 1, 2 0)",
+			"LINESTRING(0 0,1 1,2 0)",
+			LineString{{0, 0}, {1, 1}, {2, 0}},
+		},
+		{"LINESTRING EMPTY", "LINESTRING EMPTY", LineString{}},
+		{
+			"POLYGON((0 0,4 0,4 4,0 0),(1 1,2 1,2 2,1 1))",
+			"POLYGON((0 0,4 0,4 4,0 0),(1 1,2 1,2 2,1 1))",
+			Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 0}},
+				{{1, 1}, {2, 1}, {2, 2}, {1, 1}},
+			},
+		},
+		{"POLYGON EMPTY", "POLYGON EMPTY", Polygon{}},
+	}
+
+	for _, s := range samples {
+		t.Run(s.input, func(t *testing.T) {
+			g, err := ParseGeometry(s.input)
+			require.NoError(t, err)
+			assert.Equal(t, NewGeometry(s.shape), g)
+
+			shape, err := g.Shape()
+			require.NoError(t, err)
+			assert.Equal(t, s.shape, shape)
+
+			wkt, err := g.WKT()
+			require.NoError(t, err)
+			assert.Equal(t, s.output, wkt)
+		})
+	}
+
+	for _, input := range []string{
+		"POINT(1)", "POINT(1 2", "LINESTRING(1 2,)", "POINT(1 2) x",
+	} {
+		_, err := ParseGeometry(input)
+		assert.EqualError(t, err, "invalid wkt: \""+input+"\"")
+	}
+
+	_, err := ParseGeometry("MULTIPOINT(1 2)")
+	assert.EqualError(t, err, "unsupported wkt: \"MULTIPOINT(1 2)\"")
+}
+
+func TestGeometryWKB(t *testing.T) {
+	point := []byte{
+		1,          // little endian
+		1, 0, 0, 0, // point
+		0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // 1
+		0, 0, 0, 0, 0, 0, 0, 0x40, // 2
+	}
+	assert.Equal(t, point, NewGeometry(Point{1, 2}).WKB())
+
+	// big endian extended wkb with an SRID
+	g := NewGeographyFromWKB([]byte{
+		0,             // big endian
+		0x20, 0, 0, 1, // point with SRID
+		0, 0, 0x10, 0xe6, // 4326
+		0x3f, 0xf0, 0, 0, 0, 0, 0, 0, // 1
+		0x40, 0, 0, 0, 0, 0, 0, 0, // 2
+	})
+	shape, err := g.Shape()
+	require.NoError(t, err)
+	assert.Equal(t, Point{1, 2}, shape)
+
+	// point with a Z coordinate
+	_, err = NewGeometryFromWKB([]byte{
+		1, 0xe9, 0x03, 0, 0, // 1001
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+	}).Shape()
+	assert.EqualError(t, err,
+		"unsupported wkb: only two dimensional shapes are supported")
+
+	_, err = NewGeometryFromWKB(point[:10]).Shape()
+	assert.EqualError(t, err, "malformed wkb: unexpected end of data")
+
+	assert.Equal(t, point, NewBox2DFromWKB(point).WKB())
+}
+
+func TestPostGISJSON(t *testing.T) {
+	point := NewGeometry(Point{1, 2})
+	wkb := `"0101000000000000000000F03F0000000000000040"`
+
+	samples := []struct {
+		name  string
+		value interface{}
+		out   interface{}
+	}{
+		{"Geometry", point, &Geometry{}},
+		{"Geography", NewGeographyFromWKB(point.WKB()), &Geography{}},
+		{"Box2D", NewBox2DFromWKB(point.WKB()), &Box2D{}},
+		{"Box3D", NewBox3DFromWKB(point.WKB()), &Box3D{}},
+	}
+
+	for _, s := range samples {
+		t.Run(s.name, func(t *testing.T) {
+			data, err := json.Marshal(s.value)
+			require.NoError(t, err)
+			assert.Equal(t, wkb, string(data))
+
+			require.NoError(t, json.Unmarshal(data, s.out))
+			assert.Equal(t, s.value, reflectElem(s.out))
+
+			err = json.Unmarshal([]byte(`"0G"`), s.out)
+			assert.EqualError(t, err, "invalid well-known binary: "+
+				"encoding/hex: invalid byte: U+0047 'G'")
+		})
+	}
+}
+
+func TestOptionalPostGISJSON(t *testing.T) {
+	point := NewGeometry(Point{1, 2})
+	wkb := `"0101000000000000000000F03F0000000000000040"`
+
+	samples := []struct {
+		name    string
+		value   interface{}
+		missing interface{}
+		out     interface{}
+	}{
+		{
+			"OptionalGeometry",
+			NewOptionalGeometry(point),
+			OptionalGeometry{},
+			&OptionalGeometry{},
+		},
+		{
+			"OptionalGeography",
+			NewOptionalGeography(NewGeographyFromWKB(point.WKB())),
+			OptionalGeography{},
+			&OptionalGeography{},
+		},
+		{
+			"OptionalBox2D",
+			NewOptionalBox2D(NewBox2DFromWKB(point.WKB())),
+			OptionalBox2D{},
+			&OptionalBox2D{},
+		},
+		{
+			"OptionalBox3D",
+			NewOptionalBox3D(NewBox3DFromWKB(point.WKB())),
+			OptionalBox3D{},
+			&OptionalBox3D{},
+		},
+	}
+
+	for _, s := range samples {
+		t.Run(s.name, func(t *testing.T) {
+			data, err := json.Marshal(s.value)
+			require.NoError(t, err)
+			assert.Equal(t, wkb, string(data))
+
+			require.NoError(t, json.Unmarshal(data, s.out))
+			assert.Equal(t, s.value, reflectElem(s.out))
+
+			data, err = json.Marshal(s.missing)
+			require.NoError(t, err)
+			assert.Equal(t, "null", string(data))
+
+			require.NoError(t, json.Unmarshal(data, s.out))
+			assert.Equal(t, s.missing, reflectElem(s.out))
+		})
+	}
+}
+
+func reflectElem(ptr interface{}) interface{} {
+	return reflect.ValueOf(ptr).Elem().Interface()
+}
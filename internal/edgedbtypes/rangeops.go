@@ -0,0 +1,851 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// bound is one end of a span. A bound that is not set is infinite.
+type bound[T any] struct {
+	val   T
+	isSet bool
+	inc   bool
+}
+
+// span is the element type independent form of a range that the range
+// operations are implemented on.
+type span[T any] struct {
+	lower bound[T]
+	upper bound[T]
+	empty bool
+}
+
+// rangeOps implements range operations for spans of T. Spans must be
+// canonical and the results must be canonicalized by the range constructor.
+type rangeOps[T any] struct {
+	compare func(a, b T) int
+}
+
+type ordered interface {
+	~int32 | ~int64 | ~float32 | ~float64
+}
+
+func compareOrdered[T ordered](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareLocalDateTime(a, b LocalDateTime) int {
+	return compareOrdered(a.usec, b.usec)
+}
+
+func compareLocalDate(a, b LocalDate) int {
+	return compareOrdered(a.days, b.days)
+}
+
+func errNotContiguous(op string) error {
+	return fmt.Errorf("result of range %v would not be contiguous", op)
+}
+
+// compareLower compares lower bounds. Lower bounds that are not set come
+// first and inclusive bounds come before exclusive bounds of the same value.
+func (o rangeOps[T]) compareLower(a, b bound[T]) int {
+	switch {
+	case !a.isSet && !b.isSet:
+		return 0
+	case !a.isSet:
+		return -1
+	case !b.isSet:
+		return 1
+	}
+
+	if c := o.compare(a.val, b.val); c != 0 {
+		return c
+	}
+
+	switch {
+	case a.inc == b.inc:
+		return 0
+	case a.inc:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareUpper compares upper bounds. Upper bounds that are not set come
+// last and inclusive bounds come after exclusive bounds of the same value.
+func (o rangeOps[T]) compareUpper(a, b bound[T]) int {
+	switch {
+	case !a.isSet && !b.isSet:
+		return 0
+	case !a.isSet:
+		return 1
+	case !b.isSet:
+		return -1
+	}
+
+	if c := o.compare(a.val, b.val); c != 0 {
+		return c
+	}
+
+	switch {
+	case a.inc == b.inc:
+		return 0
+	case a.inc:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// reaches returns true if at least one value is
+// at or above lower and at or below upper.
+func (o rangeOps[T]) reaches(lower, upper bound[T]) bool {
+	if !lower.isSet || !upper.isSet {
+		return true
+	}
+
+	c := o.compare(lower.val, upper.val)
+	return c < 0 || c == 0 && lower.inc && upper.inc
+}
+
+// touches returns true if upper ends exactly where lower begins.
+func (o rangeOps[T]) touches(upper, lower bound[T]) bool {
+	return upper.isSet &&
+		lower.isSet &&
+		upper.inc != lower.inc &&
+		o.compare(upper.val, lower.val) == 0
+}
+
+func (o rangeOps[T]) contains(s span[T], v T) bool {
+	if s.empty {
+		return false
+	}
+
+	point := bound[T]{val: v, isSet: true, inc: true}
+	return o.reaches(s.lower, point) && o.reaches(point, s.upper)
+}
+
+func (o rangeOps[T]) containsSpan(a, b span[T]) bool {
+	switch {
+	case b.empty:
+		return true
+	case a.empty:
+		return false
+	default:
+		return o.compareLower(a.lower, b.lower) <= 0 &&
+			o.compareUpper(a.upper, b.upper) >= 0
+	}
+}
+
+func (o rangeOps[T]) overlaps(a, b span[T]) bool {
+	return !a.empty &&
+		!b.empty &&
+		o.reaches(a.lower, b.upper) &&
+		o.reaches(b.lower, a.upper)
+}
+
+func (o rangeOps[T]) adjacent(a, b span[T]) bool {
+	return !a.empty &&
+		!b.empty &&
+		(o.touches(a.upper, b.lower) || o.touches(b.upper, a.lower))
+}
+
+func (o rangeOps[T]) union(a, b span[T]) (span[T], error) {
+	switch {
+	case a.empty:
+		return b, nil
+	case b.empty:
+		return a, nil
+	case !o.overlaps(a, b) && !o.adjacent(a, b):
+		return span[T]{}, errNotContiguous("union")
+	}
+
+	if o.compareLower(b.lower, a.lower) < 0 {
+		a.lower = b.lower
+	}
+
+	if o.compareUpper(b.upper, a.upper) > 0 {
+		a.upper = b.upper
+	}
+
+	return a, nil
+}
+
+func (o rangeOps[T]) intersect(a, b span[T]) span[T] {
+	if !o.overlaps(a, b) {
+		return span[T]{empty: true}
+	}
+
+	if o.compareLower(b.lower, a.lower) > 0 {
+		a.lower = b.lower
+	}
+
+	if o.compareUpper(b.upper, a.upper) < 0 {
+		a.upper = b.upper
+	}
+
+	return a
+}
+
+func (o rangeOps[T]) difference(a, b span[T]) (span[T], error) {
+	if !o.overlaps(a, b) {
+		return a, nil
+	}
+
+	lower := o.compareLower(b.lower, a.lower)
+	upper := o.compareUpper(b.upper, a.upper)
+	switch {
+	case lower > 0 && upper < 0:
+		return span[T]{}, errNotContiguous("difference")
+	case lower <= 0 && upper >= 0:
+		return span[T]{empty: true}, nil
+	case lower <= 0:
+		a.lower = bound[T]{val: b.upper.val, isSet: true, inc: !b.upper.inc}
+	default:
+		a.upper = bound[T]{val: b.lower.val, isSet: true, inc: !b.lower.inc}
+	}
+
+	return a, nil
+}
+
+// normalize sorts spans and merges the ones that overlap or are adjacent.
+// Empty spans are removed.
+func (o rangeOps[T]) normalize(spans []span[T]) []span[T] {
+	sorted := make([]span[T], 0, len(spans))
+	for _, s := range spans {
+		if !s.empty {
+			sorted = append(sorted, s)
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return o.compareLower(sorted[i].lower, sorted[j].lower) < 0
+	})
+
+	merged := make([]span[T], 0, len(sorted))
+	for _, s := range sorted {
+		last := len(merged) - 1
+		if last >= 0 &&
+			(o.overlaps(merged[last], s) || o.adjacent(merged[last], s)) {
+			if o.compareUpper(s.upper, merged[last].upper) > 0 {
+				merged[last].upper = s.upper
+			}
+			continue
+		}
+
+		merged = append(merged, s)
+	}
+
+	return merged
+}
+
+var rangeInt32Ops = rangeOps[int32]{compareOrdered[int32]}
+
+func (r RangeInt32) span() span[int32] {
+	if r.empty {
+		return span[int32]{empty: true}
+	}
+
+	r = NewRangeInt32(r.lower, r.upper, r.incLower, r.incUpper)
+	return span[int32]{
+		lower: bound[int32]{r.lower.val, r.lower.isSet, r.incLower},
+		upper: bound[int32]{r.upper.val, r.upper.isSet, r.incUpper},
+		empty: r.empty,
+	}
+}
+
+func rangeInt32FromSpan(s span[int32]) RangeInt32 {
+	if s.empty {
+		return RangeInt32{empty: true}
+	}
+
+	var lower, upper OptionalInt32
+	if s.lower.isSet {
+		lower.Set(s.lower.val)
+	}
+
+	if s.upper.isSet {
+		upper.Set(s.upper.val)
+	}
+
+	return NewRangeInt32(lower, upper, s.lower.inc, s.upper.inc)
+}
+
+// Contains returns true if v is in r.
+func (r RangeInt32) Contains(v int32) bool {
+	return rangeInt32Ops.contains(r.span(), v)
+}
+
+// ContainsRange returns true if every value in other is in r.
+func (r RangeInt32) ContainsRange(other RangeInt32) bool {
+	return rangeInt32Ops.containsSpan(r.span(), other.span())
+}
+
+// Overlaps returns true if r and other have any values in common.
+func (r RangeInt32) Overlaps(other RangeInt32) bool {
+	return rangeInt32Ops.overlaps(r.span(), other.span())
+}
+
+// Adjacent returns true if r and other do not overlap
+// and one of them ends where the other begins.
+func (r RangeInt32) Adjacent(other RangeInt32) bool {
+	return rangeInt32Ops.adjacent(r.span(), other.span())
+}
+
+// Union returns the range of values in r or other. It returns an error
+// if r and other neither overlap nor are adjacent.
+func (r RangeInt32) Union(other RangeInt32) (RangeInt32, error) {
+	s, err := rangeInt32Ops.union(r.span(), other.span())
+	if err != nil {
+		return RangeInt32{}, err
+	}
+
+	return rangeInt32FromSpan(s), nil
+}
+
+// Intersect returns the range of values in both r and other.
+func (r RangeInt32) Intersect(other RangeInt32) RangeInt32 {
+	s := rangeInt32Ops.intersect(r.span(), other.span())
+	return rangeInt32FromSpan(s)
+}
+
+// Difference returns the range of values in r that are not in other.
+// It returns an error if the result would be two ranges.
+func (r RangeInt32) Difference(other RangeInt32) (RangeInt32, error) {
+	s, err := rangeInt32Ops.difference(r.span(), other.span())
+	if err != nil {
+		return RangeInt32{}, err
+	}
+
+	return rangeInt32FromSpan(s), nil
+}
+
+var rangeInt64Ops = rangeOps[int64]{compareOrdered[int64]}
+
+func (r RangeInt64) span() span[int64] {
+	if r.empty {
+		return span[int64]{empty: true}
+	}
+
+	r = NewRangeInt64(r.lower, r.upper, r.incLower, r.incUpper)
+	return span[int64]{
+		lower: bound[int64]{r.lower.val, r.lower.isSet, r.incLower},
+		upper: bound[int64]{r.upper.val, r.upper.isSet, r.incUpper},
+		empty: r.empty,
+	}
+}
+
+func rangeInt64FromSpan(s span[int64]) RangeInt64 {
+	if s.empty {
+		return RangeInt64{empty: true}
+	}
+
+	var lower, upper OptionalInt64
+	if s.lower.isSet {
+		lower.Set(s.lower.val)
+	}
+
+	if s.upper.isSet {
+		upper.Set(s.upper.val)
+	}
+
+	return NewRangeInt64(lower, upper, s.lower.inc, s.upper.inc)
+}
+
+// Contains returns true if v is in r.
+func (r RangeInt64) Contains(v int64) bool {
+	return rangeInt64Ops.contains(r.span(), v)
+}
+
+// ContainsRange returns true if every value in other is in r.
+func (r RangeInt64) ContainsRange(other RangeInt64) bool {
+	return rangeInt64Ops.containsSpan(r.span(), other.span())
+}
+
+// Overlaps returns true if r and other have any values in common.
+func (r RangeInt64) Overlaps(other RangeInt64) bool {
+	return rangeInt64Ops.overlaps(r.span(), other.span())
+}
+
+// Adjacent returns true if r and other do not overlap
+// and one of them ends where the other begins.
+func (r RangeInt64) Adjacent(other RangeInt64) bool {
+	return rangeInt64Ops.adjacent(r.span(), other.span())
+}
+
+// Union returns the range of values in r or other. It returns an error
+// if r and other neither overlap nor are adjacent.
+func (r RangeInt64) Union(other RangeInt64) (RangeInt64, error) {
+	s, err := rangeInt64Ops.union(r.span(), other.span())
+	if err != nil {
+		return RangeInt64{}, err
+	}
+
+	return rangeInt64FromSpan(s), nil
+}
+
+// Intersect returns the range of values in both r and other.
+func (r RangeInt64) Intersect(other RangeInt64) RangeInt64 {
+	s := rangeInt64Ops.intersect(r.span(), other.span())
+	return rangeInt64FromSpan(s)
+}
+
+// Difference returns the range of values in r that are not in other.
+// It returns an error if the result would be two ranges.
+func (r RangeInt64) Difference(other RangeInt64) (RangeInt64, error) {
+	s, err := rangeInt64Ops.difference(r.span(), other.span())
+	if err != nil {
+		return RangeInt64{}, err
+	}
+
+	return rangeInt64FromSpan(s), nil
+}
+
+var rangeFloat32Ops = rangeOps[float32]{compareOrdered[float32]}
+
+func (r RangeFloat32) span() span[float32] {
+	if r.empty {
+		return span[float32]{empty: true}
+	}
+
+	r = NewRangeFloat32(r.lower, r.upper, r.incLower, r.incUpper)
+	return span[float32]{
+		lower: bound[float32]{r.lower.val, r.lower.isSet, r.incLower},
+		upper: bound[float32]{r.upper.val, r.upper.isSet, r.incUpper},
+		empty: r.empty,
+	}
+}
+
+func rangeFloat32FromSpan(s span[float32]) RangeFloat32 {
+	if s.empty {
+		return RangeFloat32{empty: true}
+	}
+
+	var lower, upper OptionalFloat32
+	if s.lower.isSet {
+		lower.Set(s.lower.val)
+	}
+
+	if s.upper.isSet {
+		upper.Set(s.upper.val)
+	}
+
+	return NewRangeFloat32(lower, upper, s.lower.inc, s.upper.inc)
+}
+
+// Contains returns true if v is in r.
+func (r RangeFloat32) Contains(v float32) bool {
+	return rangeFloat32Ops.contains(r.span(), v)
+}
+
+// ContainsRange returns true if every value in other is in r.
+func (r RangeFloat32) ContainsRange(other RangeFloat32) bool {
+	return rangeFloat32Ops.containsSpan(r.span(), other.span())
+}
+
+// Overlaps returns true if r and other have any values in common.
+func (r RangeFloat32) Overlaps(other RangeFloat32) bool {
+	return rangeFloat32Ops.overlaps(r.span(), other.span())
+}
+
+// Adjacent returns true if r and other do not overlap
+// and one of them ends where the other begins.
+func (r RangeFloat32) Adjacent(other RangeFloat32) bool {
+	return rangeFloat32Ops.adjacent(r.span(), other.span())
+}
+
+// Union returns the range of values in r or other. It returns an error
+// if r and other neither overlap nor are adjacent.
+func (r RangeFloat32) Union(other RangeFloat32) (RangeFloat32, error) {
+	s, err := rangeFloat32Ops.union(r.span(), other.span())
+	if err != nil {
+		return RangeFloat32{}, err
+	}
+
+	return rangeFloat32FromSpan(s), nil
+}
+
+// Intersect returns the range of values in both r and other.
+func (r RangeFloat32) Intersect(other RangeFloat32) RangeFloat32 {
+	s := rangeFloat32Ops.intersect(r.span(), other.span())
+	return rangeFloat32FromSpan(s)
+}
+
+// Difference returns the range of values in r that are not in other.
+// It returns an error if the result would be two ranges.
+func (r RangeFloat32) Difference(other RangeFloat32) (RangeFloat32, error) {
+	s, err := rangeFloat32Ops.difference(r.span(), other.span())
+	if err != nil {
+		return RangeFloat32{}, err
+	}
+
+	return rangeFloat32FromSpan(s), nil
+}
+
+var rangeFloat64Ops = rangeOps[float64]{compareOrdered[float64]}
+
+func (r RangeFloat64) span() span[float64] {
+	if r.empty {
+		return span[float64]{empty: true}
+	}
+
+	r = NewRangeFloat64(r.lower, r.upper, r.incLower, r.incUpper)
+	return span[float64]{
+		lower: bound[float64]{r.lower.val, r.lower.isSet, r.incLower},
+		upper: bound[float64]{r.upper.val, r.upper.isSet, r.incUpper},
+		empty: r.empty,
+	}
+}
+
+func rangeFloat64FromSpan(s span[float64]) RangeFloat64 {
+	if s.empty {
+		return RangeFloat64{empty: true}
+	}
+
+	var lower, upper OptionalFloat64
+	if s.lower.isSet {
+		lower.Set(s.lower.val)
+	}
+
+	if s.upper.isSet {
+		upper.Set(s.upper.val)
+	}
+
+	return NewRangeFloat64(lower, upper, s.lower.inc, s.upper.inc)
+}
+
+// Contains returns true if v is in r.
+func (r RangeFloat64) Contains(v float64) bool {
+	return rangeFloat64Ops.contains(r.span(), v)
+}
+
+// ContainsRange returns true if every value in other is in r.
+func (r RangeFloat64) ContainsRange(other RangeFloat64) bool {
+	return rangeFloat64Ops.containsSpan(r.span(), other.span())
+}
+
+// Overlaps returns true if r and other have any values in common.
+func (r RangeFloat64) Overlaps(other RangeFloat64) bool {
+	return rangeFloat64Ops.overlaps(r.span(), other.span())
+}
+
+// Adjacent returns true if r and other do not overlap
+// and one of them ends where the other begins.
+func (r RangeFloat64) Adjacent(other RangeFloat64) bool {
+	return rangeFloat64Ops.adjacent(r.span(), other.span())
+}
+
+// Union returns the range of values in r or other. It returns an error
+// if r and other neither overlap nor are adjacent.
+func (r RangeFloat64) Union(other RangeFloat64) (RangeFloat64, error) {
+	s, err := rangeFloat64Ops.union(r.span(), other.span())
+	if err != nil {
+		return RangeFloat64{}, err
+	}
+
+	return rangeFloat64FromSpan(s), nil
+}
+
+// Intersect returns the range of values in both r and other.
+func (r RangeFloat64) Intersect(other RangeFloat64) RangeFloat64 {
+	s := rangeFloat64Ops.intersect(r.span(), other.span())
+	return rangeFloat64FromSpan(s)
+}
+
+// Difference returns the range of values in r that are not in other.
+// It returns an error if the result would be two ranges.
+func (r RangeFloat64) Difference(other RangeFloat64) (RangeFloat64, error) {
+	s, err := rangeFloat64Ops.difference(r.span(), other.span())
+	if err != nil {
+		return RangeFloat64{}, err
+	}
+
+	return rangeFloat64FromSpan(s), nil
+}
+
+var rangeDateTimeOps = rangeOps[time.Time]{compareTime}
+
+func (r RangeDateTime) span() span[time.Time] {
+	if r.empty {
+		return span[time.Time]{empty: true}
+	}
+
+	r = NewRangeDateTime(r.lower, r.upper, r.incLower, r.incUpper)
+	return span[time.Time]{
+		lower: bound[time.Time]{r.lower.val, r.lower.isSet, r.incLower},
+		upper: bound[time.Time]{r.upper.val, r.upper.isSet, r.incUpper},
+		empty: r.empty,
+	}
+}
+
+func rangeDateTimeFromSpan(s span[time.Time]) RangeDateTime {
+	if s.empty {
+		return RangeDateTime{empty: true}
+	}
+
+	var lower, upper OptionalDateTime
+	if s.lower.isSet {
+		lower.Set(s.lower.val)
+	}
+
+	if s.upper.isSet {
+		upper.Set(s.upper.val)
+	}
+
+	return NewRangeDateTime(lower, upper, s.lower.inc, s.upper.inc)
+}
+
+// Contains returns true if v is in r.
+func (r RangeDateTime) Contains(v time.Time) bool {
+	return rangeDateTimeOps.contains(r.span(), v)
+}
+
+// ContainsRange returns true if every value in other is in r.
+func (r RangeDateTime) ContainsRange(other RangeDateTime) bool {
+	return rangeDateTimeOps.containsSpan(r.span(), other.span())
+}
+
+// Overlaps returns true if r and other have any values in common.
+func (r RangeDateTime) Overlaps(other RangeDateTime) bool {
+	return rangeDateTimeOps.overlaps(r.span(), other.span())
+}
+
+// Adjacent returns true if r and other do not overlap
+// and one of them ends where the other begins.
+func (r RangeDateTime) Adjacent(other RangeDateTime) bool {
+	return rangeDateTimeOps.adjacent(r.span(), other.span())
+}
+
+// Union returns the range of values in r or other. It returns an error
+// if r and other neither overlap nor are adjacent.
+func (r RangeDateTime) Union(other RangeDateTime) (RangeDateTime, error) {
+	s, err := rangeDateTimeOps.union(r.span(), other.span())
+	if err != nil {
+		return RangeDateTime{}, err
+	}
+
+	return rangeDateTimeFromSpan(s), nil
+}
+
+// Intersect returns the range of values in both r and other.
+func (r RangeDateTime) Intersect(other RangeDateTime) RangeDateTime {
+	s := rangeDateTimeOps.intersect(r.span(), other.span())
+	return rangeDateTimeFromSpan(s)
+}
+
+// Difference returns the range of values in r that are not in other.
+// It returns an error if the result would be two ranges.
+func (r RangeDateTime) Difference(other RangeDateTime) (RangeDateTime, error) {
+	s, err := rangeDateTimeOps.difference(r.span(), other.span())
+	if err != nil {
+		return RangeDateTime{}, err
+	}
+
+	return rangeDateTimeFromSpan(s), nil
+}
+
+var rangeLocalDateTimeOps = rangeOps[LocalDateTime]{compareLocalDateTime}
+
+func (r RangeLocalDateTime) span() span[LocalDateTime] {
+	if r.empty {
+		return span[LocalDateTime]{empty: true}
+	}
+
+	r = NewRangeLocalDateTime(r.lower, r.upper, r.incLower, r.incUpper)
+	return span[LocalDateTime]{
+		lower: bound[LocalDateTime]{r.lower.val, r.lower.isSet, r.incLower},
+		upper: bound[LocalDateTime]{r.upper.val, r.upper.isSet, r.incUpper},
+		empty: r.empty,
+	}
+}
+
+func rangeLocalDateTimeFromSpan(s span[LocalDateTime]) RangeLocalDateTime {
+	if s.empty {
+		return RangeLocalDateTime{empty: true}
+	}
+
+	var lower, upper OptionalLocalDateTime
+	if s.lower.isSet {
+		lower.Set(s.lower.val)
+	}
+
+	if s.upper.isSet {
+		upper.Set(s.upper.val)
+	}
+
+	return NewRangeLocalDateTime(lower, upper, s.lower.inc, s.upper.inc)
+}
+
+// Contains returns true if v is in r.
+func (r RangeLocalDateTime) Contains(v LocalDateTime) bool {
+	return rangeLocalDateTimeOps.contains(r.span(), v)
+}
+
+// ContainsRange returns true if every value in other is in r.
+func (r RangeLocalDateTime) ContainsRange(other RangeLocalDateTime) bool {
+	return rangeLocalDateTimeOps.containsSpan(r.span(), other.span())
+}
+
+// Overlaps returns true if r and other have any values in common.
+func (r RangeLocalDateTime) Overlaps(other RangeLocalDateTime) bool {
+	return rangeLocalDateTimeOps.overlaps(r.span(), other.span())
+}
+
+// Adjacent returns true if r and other do not overlap
+// and one of them ends where the other begins.
+func (r RangeLocalDateTime) Adjacent(other RangeLocalDateTime) bool {
+	return rangeLocalDateTimeOps.adjacent(r.span(), other.span())
+}
+
+// Union returns the range of values in r or other. It returns an error
+// if r and other neither overlap nor are adjacent.
+func (r RangeLocalDateTime) Union(
+	other RangeLocalDateTime,
+) (RangeLocalDateTime, error) {
+	s, err := rangeLocalDateTimeOps.union(r.span(), other.span())
+	if err != nil {
+		return RangeLocalDateTime{}, err
+	}
+
+	return rangeLocalDateTimeFromSpan(s), nil
+}
+
+// Intersect returns the range of values in both r and other.
+func (r RangeLocalDateTime) Intersect(
+	other RangeLocalDateTime,
+) RangeLocalDateTime {
+	s := rangeLocalDateTimeOps.intersect(r.span(), other.span())
+	return rangeLocalDateTimeFromSpan(s)
+}
+
+// Difference returns the range of values in r that are not in other.
+// It returns an error if the result would be two ranges.
+func (r RangeLocalDateTime) Difference(
+	other RangeLocalDateTime,
+) (RangeLocalDateTime, error) {
+	s, err := rangeLocalDateTimeOps.difference(r.span(), other.span())
+	if err != nil {
+		return RangeLocalDateTime{}, err
+	}
+
+	return rangeLocalDateTimeFromSpan(s), nil
+}
+
+var rangeLocalDateOps = rangeOps[LocalDate]{compareLocalDate}
+
+func (r RangeLocalDate) span() span[LocalDate] {
+	if r.empty {
+		return span[LocalDate]{empty: true}
+	}
+
+	r = NewRangeLocalDate(r.lower, r.upper, r.incLower, r.incUpper)
+	return span[LocalDate]{
+		lower: bound[LocalDate]{r.lower.val, r.lower.isSet, r.incLower},
+		upper: bound[LocalDate]{r.upper.val, r.upper.isSet, r.incUpper},
+		empty: r.empty,
+	}
+}
+
+func rangeLocalDateFromSpan(s span[LocalDate]) RangeLocalDate {
+	if s.empty {
+		return RangeLocalDate{empty: true}
+	}
+
+	var lower, upper OptionalLocalDate
+	if s.lower.isSet {
+		lower.Set(s.lower.val)
+	}
+
+	if s.upper.isSet {
+		upper.Set(s.upper.val)
+	}
+
+	return NewRangeLocalDate(lower, upper, s.lower.inc, s.upper.inc)
+}
+
+// Contains returns true if v is in r.
+func (r RangeLocalDate) Contains(v LocalDate) bool {
+	return rangeLocalDateOps.contains(r.span(), v)
+}
+
+// ContainsRange returns true if every value in other is in r.
+func (r RangeLocalDate) ContainsRange(other RangeLocalDate) bool {
+	return rangeLocalDateOps.containsSpan(r.span(), other.span())
+}
+
+// Overlaps returns true if r and other have any values in common.
+func (r RangeLocalDate) Overlaps(other RangeLocalDate) bool {
+	return rangeLocalDateOps.overlaps(r.span(), other.span())
+}
+
+// Adjacent returns true if r and other do not overlap
+// and one of them ends where the other begins.
+func (r RangeLocalDate) Adjacent(other RangeLocalDate) bool {
+	return rangeLocalDateOps.adjacent(r.span(), other.span())
+}
+
+// Union returns the range of values in r or other. It returns an error
+// if r and other neither overlap nor are adjacent.
+func (r RangeLocalDate) Union(other RangeLocalDate) (RangeLocalDate, error) {
+	s, err := rangeLocalDateOps.union(r.span(), other.span())
+	if err != nil {
+		return RangeLocalDate{}, err
+	}
+
+	return rangeLocalDateFromSpan(s), nil
+}
+
+// Intersect returns the range of values in both r and other.
+func (r RangeLocalDate) Intersect(other RangeLocalDate) RangeLocalDate {
+	s := rangeLocalDateOps.intersect(r.span(), other.span())
+	return rangeLocalDateFromSpan(s)
+}
+
+// Difference returns the range of values in r that are not in other.
+// It returns an error if the result would be two ranges.
+func (r RangeLocalDate) Difference(
+	other RangeLocalDate,
+) (RangeLocalDate, error) {
+	s, err := rangeLocalDateOps.difference(r.span(), other.span())
+	if err != nil {
+		return RangeLocalDate{}, err
+	}
+
+	return rangeLocalDateFromSpan(s), nil
+}
@@ -0,0 +1,228 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Range(lower, upper int64, incLower, incUpper bool) RangeInt64 {
+	return NewRangeInt64(
+		NewOptionalInt64(lower),
+		NewOptionalInt64(upper),
+		incLower,
+		incUpper,
+	)
+}
+
+func float64Range(lower, upper float64, incLower, incUpper bool) RangeFloat64 {
+	return NewRangeFloat64(
+		NewOptionalFloat64(lower),
+		NewOptionalFloat64(upper),
+		incLower,
+		incUpper,
+	)
+}
+
+func TestRangeContains(t *testing.T) {
+	r := int64Range(1, 5, true, false)
+	assert.True(t, r.Contains(1))
+	assert.True(t, r.Contains(4))
+	assert.False(t, r.Contains(5))
+	assert.False(t, r.Contains(0))
+	assert.False(t, RangeInt64{empty: true}.Contains(0))
+
+	unbounded := NewRangeInt64(
+		OptionalInt64{}, NewOptionalInt64(0), false, true)
+	assert.True(t, unbounded.Contains(-1_000))
+	assert.True(t, unbounded.Contains(0))
+	assert.False(t, unbounded.Contains(1))
+
+	f := float64Range(1, 2, false, true)
+	assert.False(t, f.Contains(1))
+	assert.True(t, f.Contains(1.5))
+	assert.True(t, f.Contains(2))
+
+	assert.True(t, r.ContainsRange(int64Range(2, 4, true, true)))
+	assert.True(t, r.ContainsRange(RangeInt64{empty: true}))
+	assert.False(t, r.ContainsRange(int64Range(2, 5, true, true)))
+	assert.True(t, f.ContainsRange(float64Range(1.5, 2, true, true)))
+	assert.False(t, f.ContainsRange(float64Range(1, 2, true, true)))
+}
+
+func TestRangeCanonicalization(t *testing.T) {
+	// ranges that were not made with a constructor are canonicalized
+	// before they are compared
+	r := RangeInt64{
+		lower:    NewOptionalInt64(1),
+		upper:    NewOptionalInt64(4),
+		incLower: false,
+		incUpper: true,
+	}
+	assert.False(t, r.Contains(1))
+	assert.True(t, r.Contains(4))
+	assert.True(t, r.Adjacent(int64Range(5, 6, true, false)))
+
+	intersect := r.Intersect(int64Range(0, 10, true, false))
+	assert.Equal(t, int64Range(2, 5, true, false), intersect)
+}
+
+func TestRangeOverlapsAndAdjacent(t *testing.T) {
+	a := int64Range(1, 3, true, false)
+	b := int64Range(3, 5, true, false)
+	assert.False(t, a.Overlaps(b))
+	assert.True(t, a.Adjacent(b))
+	assert.True(t, b.Adjacent(a))
+	assert.True(t, a.Overlaps(int64Range(2, 5, true, false)))
+	assert.False(t, a.Adjacent(int64Range(2, 5, true, false)))
+	assert.False(t, a.Overlaps(RangeInt64{empty: true}))
+
+	// [1, 2] and [3, 4] are adjacent integer ranges
+	assert.True(t, int64Range(1, 2, true, true).
+		Adjacent(int64Range(3, 4, true, true)))
+
+	f := float64Range(1, 2, true, true)
+	assert.True(t, f.Overlaps(float64Range(2, 3, true, false)))
+	assert.False(t, f.Overlaps(float64Range(2, 3, false, false)))
+	assert.True(t, f.Adjacent(float64Range(2, 3, false, false)))
+	assert.False(t, float64Range(1, 2, true, false).
+		Adjacent(float64Range(2, 3, false, false)))
+}
+
+func TestRangeUnion(t *testing.T) {
+	a := int64Range(1, 3, true, false)
+
+	union, err := a.Union(int64Range(3, 5, true, false))
+	require.NoError(t, err)
+	assert.Equal(t, int64Range(1, 5, true, false), union)
+
+	union, err = a.Union(RangeInt64{empty: true})
+	require.NoError(t, err)
+	assert.Equal(t, a, union)
+
+	union, err = a.Union(NewRangeInt64(
+		NewOptionalInt64(2), OptionalInt64{}, true, false))
+	require.NoError(t, err)
+	assert.Equal(t, NewRangeInt64(
+		NewOptionalInt64(1), OptionalInt64{}, true, false), union)
+
+	_, err = a.Union(int64Range(4, 5, true, false))
+	assert.EqualError(t, err, "result of range union would not be contiguous")
+
+	_, err = float64Range(1, 2, true, false).
+		Union(float64Range(2, 3, false, false))
+	assert.EqualError(t, err, "result of range union would not be contiguous")
+}
+
+func TestRangeIntersect(t *testing.T) {
+	a := float64Range(1, 3, true, false)
+	assert.Equal(t, float64Range(2, 3, false, false),
+		a.Intersect(float64Range(2, 4, false, true)))
+	assert.Equal(t, RangeFloat64{empty: true},
+		a.Intersect(float64Range(3, 4, true, true)))
+	assert.Equal(t, float64Range(1, 1, true, true),
+		a.Intersect(float64Range(0, 1, true, true)))
+}
+
+func TestRangeDifference(t *testing.T) {
+	a := int64Range(1, 10, true, false)
+
+	diff, err := a.Difference(int64Range(5, 20, true, false))
+	require.NoError(t, err)
+	assert.Equal(t, int64Range(1, 5, true, false), diff)
+
+	diff, err = a.Difference(int64Range(0, 5, true, false))
+	require.NoError(t, err)
+	assert.Equal(t, int64Range(5, 10, true, false), diff)
+
+	diff, err = a.Difference(int64Range(0, 10, true, false))
+	require.NoError(t, err)
+	assert.Equal(t, RangeInt64{empty: true}, diff)
+
+	diff, err = a.Difference(int64Range(20, 30, true, false))
+	require.NoError(t, err)
+	assert.Equal(t, a, diff)
+
+	_, err = a.Difference(int64Range(4, 5, true, false))
+	assert.EqualError(t, err,
+		"result of range difference would not be contiguous")
+
+	f, err := float64Range(1, 3, true, true).
+		Difference(float64Range(2, 4, true, true))
+	require.NoError(t, err)
+	assert.Equal(t, float64Range(1, 2, true, false), f)
+}
+
+func TestDateRangeOperations(t *testing.T) {
+	jan := NewRangeLocalDate(
+		NewOptionalLocalDate(NewLocalDate(2024, 1, 1)),
+		NewOptionalLocalDate(NewLocalDate(2024, 1, 31)),
+		true,
+		true,
+	)
+	feb := NewRangeLocalDate(
+		NewOptionalLocalDate(NewLocalDate(2024, 2, 1)),
+		NewOptionalLocalDate(NewLocalDate(2024, 3, 1)),
+		true,
+		false,
+	)
+
+	assert.True(t, jan.Contains(NewLocalDate(2024, 1, 31)))
+	assert.False(t, jan.Contains(NewLocalDate(2024, 2, 1)))
+	assert.True(t, jan.Adjacent(feb))
+
+	union, err := jan.Union(feb)
+	require.NoError(t, err)
+	assert.Equal(t, NewRangeLocalDate(
+		NewOptionalLocalDate(NewLocalDate(2024, 1, 1)),
+		NewOptionalLocalDate(NewLocalDate(2024, 3, 1)),
+		true,
+		false,
+	), union)
+}
+
+func TestNormalizeMultiRange(t *testing.T) {
+	m := NormalizeMultiRangeInt64(MultiRangeInt64{
+		int64Range(10, 12, true, false),
+		int64Range(1, 3, true, false),
+		{empty: true},
+		int64Range(2, 5, true, false),
+		int64Range(5, 6, true, false),
+		NewRangeInt64(NewOptionalInt64(20), OptionalInt64{}, true, false),
+		int64Range(25, 30, true, false),
+	})
+	assert.Equal(t, MultiRangeInt64{
+		int64Range(1, 6, true, false),
+		int64Range(10, 12, true, false),
+		NewRangeInt64(NewOptionalInt64(20), OptionalInt64{}, true, false),
+	}, m)
+
+	assert.Equal(t, MultiRangeInt64{},
+		NormalizeMultiRangeInt64(MultiRangeInt64{{empty: true}}))
+
+	f := NormalizeMultiRangeFloat64(MultiRangeFloat64{
+		float64Range(2, 3, false, false),
+		float64Range(1, 2, true, false),
+	})
+	assert.Equal(t, MultiRangeFloat64{
+		float64Range(1, 2, true, false),
+		float64Range(2, 3, false, false),
+	}, f)
+}
@@ -0,0 +1,1425 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types in this package implement sql.Scanner and driver.Valuer so that they
+// can be used with other database/sql drivers. Values are their text
+// representation unless there is an equivalent driver.Value type. Missing
+// optional values are NULL. Ranges are postgres range literals like [1,5),
+// vectors use the pgvector text format and PostGIS values are hex encoded
+// well-known binary. Multiranges are slices and do not implement either.
+
+func scanText(
+	src interface{},
+	name string,
+	dst encoding.TextUnmarshaler,
+) error {
+	switch in := src.(type) {
+	case string:
+		return dst.UnmarshalText([]byte(in))
+	case []byte:
+		return dst.UnmarshalText(in)
+	default:
+		return scanError(src, name)
+	}
+}
+
+func scanError(src interface{}, name string) error {
+	return fmt.Errorf("cannot scan %T into edgedb.%v", src, name)
+}
+
+func textValue(m encoding.TextMarshaler) (driver.Value, error) {
+	data, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// scanOptional scans src into a new T and sets it
+// or unsets the optional value if src is nil.
+func scanOptional[T any, PT interface {
+	*T
+	sql.Scanner
+}](src interface{}, set func(T), unset func()) error {
+	if src == nil {
+		unset()
+		return nil
+	}
+
+	var val T
+	if err := PT(&val).Scan(src); err != nil {
+		return err
+	}
+
+	set(val)
+	return nil
+}
+
+// Value returns id as a string.
+func (id UUID) Value() (driver.Value, error) { return id.String(), nil }
+
+// Scan scans a string or a 16 byte slice into *id.
+func (id *UUID) Scan(src interface{}) error {
+	if in, ok := src.([]byte); ok && len(in) == 16 {
+		copy(id[:], in)
+		return nil
+	}
+
+	return scanText(src, "UUID", id)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalUUID) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalUUID) Scan(src interface{}) error {
+	return scanOptional[UUID](src, o.Set, o.Unset)
+}
+
+// Value returns dt as text.
+func (dt LocalDateTime) Value() (driver.Value, error) {
+	return textValue(dt)
+}
+
+// Scan scans text or a time.Time into *dt.
+// The time.Time's time zone is ignored.
+func (dt *LocalDateTime) Scan(src interface{}) error {
+	switch in := src.(type) {
+	case time.Time:
+		*dt = NewLocalDateTime(in.Year(), in.Month(), in.Day(),
+			in.Hour(), in.Minute(), in.Second(), in.Nanosecond()/1_000)
+		return nil
+	case string:
+		// postgres separates the date and time with a space
+		return dt.UnmarshalText([]byte(strings.Replace(in, " ", "T", 1)))
+	case []byte:
+		return dt.Scan(string(in))
+	default:
+		return scanError(src, "LocalDateTime")
+	}
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalLocalDateTime) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalLocalDateTime) Scan(src interface{}) error {
+	return scanOptional[LocalDateTime](src, o.Set, o.Unset)
+}
+
+// Value returns d as text.
+func (d LocalDate) Value() (driver.Value, error) { return textValue(d) }
+
+// Scan scans text or a time.Time into *d.
+// The time.Time's time of day and time zone are ignored.
+func (d *LocalDate) Scan(src interface{}) error {
+	if in, ok := src.(time.Time); ok {
+		*d = NewLocalDate(in.Year(), in.Month(), in.Day())
+		return nil
+	}
+
+	return scanText(src, "LocalDate", d)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalLocalDate) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalLocalDate) Scan(src interface{}) error {
+	return scanOptional[LocalDate](src, o.Set, o.Unset)
+}
+
+// Value returns t as text.
+func (t LocalTime) Value() (driver.Value, error) { return textValue(t) }
+
+// Scan scans text or a time.Time into *t.
+// The time.Time's date and time zone are ignored.
+func (t *LocalTime) Scan(src interface{}) error {
+	if in, ok := src.(time.Time); ok {
+		*t = NewLocalTime(
+			in.Hour(), in.Minute(), in.Second(), in.Nanosecond()/1_000)
+		return nil
+	}
+
+	return scanText(src, "LocalTime", t)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalLocalTime) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalLocalTime) Scan(src interface{}) error {
+	return scanOptional[LocalTime](src, o.Set, o.Unset)
+}
+
+// Value returns d as an ISO 8601 duration string.
+func (d Duration) Value() (driver.Value, error) { return d.String(), nil }
+
+// Scan scans a duration string or an int64 microsecond count into *d.
+// Postgres interval output like 01:00:00 or 1 day 02:00:00 is accepted
+// too as long as it has no months or years. A day is 24 hours.
+func (d *Duration) Scan(src interface{}) error {
+	var str string
+	switch in := src.(type) {
+	case int64:
+		*d = Duration(in)
+		return nil
+	case string:
+		str = in
+	case []byte:
+		str = string(in)
+	default:
+		return scanError(src, "Duration")
+	}
+
+	tmp, err := ParseDuration(str)
+	if err != nil {
+		var ok bool
+		if tmp, ok = parsePostgresInterval(str); !ok {
+			return err
+		}
+	}
+
+	*d = tmp
+	return nil
+}
+
+// maxIntervalDays keeps intervals from overflowing a Duration.
+const maxIntervalDays = math.MaxInt64 / (24 * usecsPerHour) / 2
+
+// parsePostgresInterval parses an interval in the postgres IntervalStyle
+// e.g. -1 days +02:03:04.5 without months or years.
+func parsePostgresInterval(s string) (Duration, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	var usecs int64
+	for i := 0; i < len(fields); i++ {
+		if strings.Contains(fields[i], ":") {
+			t, ok := parsePostgresTime(fields[i])
+			if !ok || i != len(fields)-1 {
+				return 0, false
+			}
+			usecs += t
+			continue
+		}
+
+		if i+1 >= len(fields) {
+			return 0, false
+		}
+
+		n, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil || n > maxIntervalDays || n < -maxIntervalDays {
+			return 0, false
+		}
+
+		i++
+		switch fields[i] {
+		case "day", "days":
+			usecs += n * 24 * usecsPerHour
+		default:
+			return 0, false
+		}
+	}
+
+	return Duration(usecs), true
+}
+
+// parsePostgresTime parses [+-]HH:MM:SS[.ffffff] into microseconds.
+func parsePostgresTime(s string) (int64, bool) {
+	sign := int64(1)
+	switch s[0] {
+	case '-':
+		sign = -1
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+
+	whole, frac, _ := strings.Cut(parts[2], ".")
+	if len(frac) > 6 {
+		return 0, false
+	}
+
+	hours, ok1 := parseDigits(parts[0])
+	minutes, ok2 := parseDigits(parts[1])
+	seconds, ok3 := parseDigits(whole)
+	fracUsecs, ok4 := parseDigits(frac + strings.Repeat("0", 6-len(frac)))
+	if !ok1 || !ok2 || !ok3 || !ok4 ||
+		hours > maxIntervalDays*24 || minutes > 59 || seconds > 59 {
+		return 0, false
+	}
+
+	return sign * (hours*usecsPerHour +
+		minutes*usecsPerMinute +
+		seconds*usecsPerSecond +
+		fracUsecs), true
+}
+
+// parseDigits parses a non empty string of decimal digits.
+func parseDigits(s string) (int64, bool) {
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalDuration) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalDuration) Scan(src interface{}) error {
+	return scanOptional[Duration](src, o.Set, o.Unset)
+}
+
+// Value returns rd as text.
+func (rd RelativeDuration) Value() (driver.Value, error) {
+	return textValue(rd)
+}
+
+// Scan scans text into *rd.
+func (rd *RelativeDuration) Scan(src interface{}) error {
+	return scanText(src, "RelativeDuration", rd)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalRelativeDuration) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalRelativeDuration) Scan(src interface{}) error {
+	return scanOptional[RelativeDuration](src, o.Set, o.Unset)
+}
+
+// Value returns dd as text.
+func (dd DateDuration) Value() (driver.Value, error) { return textValue(dd) }
+
+// Scan scans text into *dd.
+func (dd *DateDuration) Scan(src interface{}) error {
+	return scanText(src, "DateDuration", dd)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalDateDuration) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalDateDuration) Scan(src interface{}) error {
+	return scanOptional[DateDuration](src, o.Set, o.Unset)
+}
+
+// Value returns m as text.
+func (m Memory) Value() (driver.Value, error) { return textValue(m) }
+
+// Scan scans text or an int64 byte count into *m.
+func (m *Memory) Scan(src interface{}) error {
+	if in, ok := src.(int64); ok {
+		*m = Memory(in)
+		return nil
+	}
+
+	return scanText(src, "Memory", m)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalMemory) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalMemory) Scan(src interface{}) error {
+	return scanOptional[Memory](src, o.Set, o.Unset)
+}
+
+// Value returns d as text.
+func (d Decimal) Value() (driver.Value, error) { return textValue(d) }
+
+// Scan scans text, an int64 or a float64 into *d.
+func (d *Decimal) Scan(src interface{}) error {
+	switch in := src.(type) {
+	case int64:
+		*d = NewDecimalFromInt64(in)
+		return nil
+	case float64:
+		tmp, err := NewDecimalFromFloat64(in)
+		if err != nil {
+			return err
+		}
+
+		*d = tmp
+		return nil
+	default:
+		return scanText(src, "Decimal", d)
+	}
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalDecimal) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalDecimal) Scan(src interface{}) error {
+	return scanOptional[Decimal](src, o.Set, o.Unset)
+}
+
+// Value returns o.Get() as a string or nil if o is missing.
+func (o OptionalBigInt) Value() (driver.Value, error) {
+	if !o.isSet || o.val == nil {
+		return nil, nil
+	}
+
+	return o.val.String(), nil
+}
+
+// Scan scans text or an int64 into *o. A nil src unsets *o.
+func (o *OptionalBigInt) Scan(src interface{}) error {
+	var str string
+	switch in := src.(type) {
+	case nil:
+		o.Unset()
+		return nil
+	case int64:
+		o.Set(big.NewInt(in))
+		return nil
+	case string:
+		str = in
+	case []byte:
+		str = string(in)
+	default:
+		return scanError(src, "OptionalBigInt")
+	}
+
+	val, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return fmt.Errorf("cannot scan %q into edgedb.OptionalBigInt", str)
+	}
+
+	o.Set(val)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalStr) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val, nil
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalStr) Scan(src interface{}) error {
+	var val sql.NullString
+	if err := val.Scan(src); err != nil {
+		return err
+	}
+
+	if !val.Valid {
+		o.Unset()
+		return nil
+	}
+
+	o.Set(val.String)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalBytes) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val, nil
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalBytes) Scan(src interface{}) error {
+	switch in := src.(type) {
+	case nil:
+		o.Unset()
+	case []byte:
+		// src may be reused by the driver so it must be copied
+		o.Set(append([]byte{}, in...))
+	case string:
+		o.Set([]byte(in))
+	default:
+		return scanError(src, "OptionalBytes")
+	}
+
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalBool) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val, nil
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalBool) Scan(src interface{}) error {
+	var val sql.NullBool
+	if err := val.Scan(src); err != nil {
+		return err
+	}
+
+	if !val.Valid {
+		o.Unset()
+		return nil
+	}
+
+	o.Set(val.Bool)
+	return nil
+}
+
+// Value returns o.Get() as an int64 or nil if o is missing.
+func (o OptionalInt16) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return int64(o.val), nil
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalInt16) Scan(src interface{}) error {
+	var val sql.NullInt16
+	if err := val.Scan(src); err != nil {
+		return err
+	}
+
+	if !val.Valid {
+		o.Unset()
+		return nil
+	}
+
+	o.Set(val.Int16)
+	return nil
+}
+
+// Value returns o.Get() as an int64 or nil if o is missing.
+func (o OptionalInt32) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return int64(o.val), nil
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalInt32) Scan(src interface{}) error {
+	var val sql.NullInt32
+	if err := val.Scan(src); err != nil {
+		return err
+	}
+
+	if !val.Valid {
+		o.Unset()
+		return nil
+	}
+
+	o.Set(val.Int32)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalInt64) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val, nil
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalInt64) Scan(src interface{}) error {
+	var val sql.NullInt64
+	if err := val.Scan(src); err != nil {
+		return err
+	}
+
+	if !val.Valid {
+		o.Unset()
+		return nil
+	}
+
+	o.Set(val.Int64)
+	return nil
+}
+
+// Value returns o.Get() as a float64 or nil if o is missing.
+func (o OptionalFloat32) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return float64(o.val), nil
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalFloat32) Scan(src interface{}) error {
+	var val sql.NullFloat64
+	if err := val.Scan(src); err != nil {
+		return err
+	}
+
+	if !val.Valid {
+		o.Unset()
+		return nil
+	}
+
+	o.Set(float32(val.Float64))
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalFloat64) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val, nil
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalFloat64) Scan(src interface{}) error {
+	var val sql.NullFloat64
+	if err := val.Scan(src); err != nil {
+		return err
+	}
+
+	if !val.Valid {
+		o.Unset()
+		return nil
+	}
+
+	o.Set(val.Float64)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalDateTime) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val, nil
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalDateTime) Scan(src interface{}) error {
+	var val sql.NullTime
+	if err := val.Scan(src); err != nil {
+		return err
+	}
+
+	if !val.Valid {
+		o.Unset()
+		return nil
+	}
+
+	o.Set(val.Time)
+	return nil
+}
+
+// Value returns the driver.Value for o.Get() or nil if o is missing.
+func (o Opt[T]) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(o.val)
+}
+
+// Scan scans src into *o. A nil src unsets *o. T must implement sql.Scanner
+// or src must be assignable to T.
+func (o *Opt[T]) Scan(src interface{}) error {
+	if src == nil {
+		o.Unset()
+		return nil
+	}
+
+	var val T
+	if scanner, ok := interface{}(&val).(sql.Scanner); ok {
+		if err := scanner.Scan(src); err != nil {
+			return err
+		}
+
+		o.Set(val)
+		return nil
+	}
+
+	if !convertAssign(reflect.ValueOf(&val).Elem(), reflect.ValueOf(src)) {
+		return fmt.Errorf("cannot scan %T into edgedb.Opt[%T]", src, val)
+	}
+
+	o.Set(val)
+	return nil
+}
+
+// convertAssign sets out to in if in is assignable to out or if both are
+// numbers or strings and in can be converted to out without losing
+// information. It returns false if out was not set.
+func convertAssign(out, in reflect.Value) bool {
+	if in.Kind() == reflect.Slice && in.Type().Elem().Kind() == reflect.Uint8 {
+		// Drivers may reuse the memory of []byte values
+		// so out must not keep a reference to it.
+		cpy := reflect.MakeSlice(in.Type(), in.Len(), in.Len())
+		reflect.Copy(cpy, in)
+		in = cpy
+	}
+
+	if in.Type().AssignableTo(out.Type()) {
+		out.Set(in)
+		return true
+	}
+
+	switch {
+	case isNumberKind(in.Kind()) && isNumberKind(out.Kind()):
+		converted := in.Convert(out.Type())
+		if converted.Convert(in.Type()).Interface() != in.Interface() {
+			return false
+		}
+
+		out.Set(converted)
+		return true
+	case isTextKind(in.Type()) && isTextKind(out.Type()):
+		out.Set(in.Convert(out.Type()))
+		return true
+	default:
+		return false
+	}
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return reflect.Int <= kind && kind <= reflect.Float64
+}
+
+func isTextKind(typ reflect.Type) bool {
+	return typ.Kind() == reflect.String ||
+		typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
+
+// Value returns r as a postgres range literal like [1,5) or empty.
+func (r RangeInt32) Value() (driver.Value, error) {
+	return formatRange(r.span(), formatInt[int32]), nil
+}
+
+// Scan scans a postgres range literal into *r.
+func (r *RangeInt32) Scan(src interface{}) error {
+	s, err := scanRange(src, "RangeInt32", parseInt32)
+	if err != nil {
+		return err
+	}
+
+	*r = rangeInt32FromSpan(s)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalRangeInt32) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalRangeInt32) Scan(src interface{}) error {
+	return scanOptional[RangeInt32](src, o.Set, o.Unset)
+}
+
+// Value returns r as a postgres range literal like [1,5) or empty.
+func (r RangeInt64) Value() (driver.Value, error) {
+	return formatRange(r.span(), formatInt[int64]), nil
+}
+
+// Scan scans a postgres range literal into *r.
+func (r *RangeInt64) Scan(src interface{}) error {
+	s, err := scanRange(src, "RangeInt64", parseInt64)
+	if err != nil {
+		return err
+	}
+
+	*r = rangeInt64FromSpan(s)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalRangeInt64) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalRangeInt64) Scan(src interface{}) error {
+	return scanOptional[RangeInt64](src, o.Set, o.Unset)
+}
+
+// Value returns r as a postgres range literal like [1,5) or empty.
+func (r RangeFloat32) Value() (driver.Value, error) {
+	return formatRange(r.span(), formatFloat32), nil
+}
+
+// Scan scans a postgres range literal into *r.
+func (r *RangeFloat32) Scan(src interface{}) error {
+	s, err := scanRange(src, "RangeFloat32", parseFloat32)
+	if err != nil {
+		return err
+	}
+
+	*r = rangeFloat32FromSpan(s)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalRangeFloat32) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalRangeFloat32) Scan(src interface{}) error {
+	return scanOptional[RangeFloat32](src, o.Set, o.Unset)
+}
+
+// Value returns r as a postgres range literal like [1,5) or empty.
+func (r RangeFloat64) Value() (driver.Value, error) {
+	return formatRange(r.span(), formatFloat64), nil
+}
+
+// Scan scans a postgres range literal into *r.
+func (r *RangeFloat64) Scan(src interface{}) error {
+	s, err := scanRange(src, "RangeFloat64", parseFloat64)
+	if err != nil {
+		return err
+	}
+
+	*r = rangeFloat64FromSpan(s)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalRangeFloat64) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalRangeFloat64) Scan(src interface{}) error {
+	return scanOptional[RangeFloat64](src, o.Set, o.Unset)
+}
+
+// Value returns r as a postgres range literal like [1,5) or empty.
+func (r RangeDateTime) Value() (driver.Value, error) {
+	return formatRange(r.span(), formatTime), nil
+}
+
+// Scan scans a postgres range literal into *r.
+func (r *RangeDateTime) Scan(src interface{}) error {
+	s, err := scanRange(src, "RangeDateTime", parseTime)
+	if err != nil {
+		return err
+	}
+
+	*r = rangeDateTimeFromSpan(s)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalRangeDateTime) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalRangeDateTime) Scan(src interface{}) error {
+	return scanOptional[RangeDateTime](src, o.Set, o.Unset)
+}
+
+// Value returns r as a postgres range literal like [1,5) or empty.
+func (r RangeLocalDateTime) Value() (driver.Value, error) {
+	return formatRange(r.span(), formatText[LocalDateTime]), nil
+}
+
+// Scan scans a postgres range literal into *r.
+func (r *RangeLocalDateTime) Scan(src interface{}) error {
+	s, err := scanRange(src, "RangeLocalDateTime", parseLocalDateTime)
+	if err != nil {
+		return err
+	}
+
+	*r = rangeLocalDateTimeFromSpan(s)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalRangeLocalDateTime) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalRangeLocalDateTime) Scan(src interface{}) error {
+	return scanOptional[RangeLocalDateTime](src, o.Set, o.Unset)
+}
+
+// Value returns r as a postgres range literal like [1,5) or empty.
+func (r RangeLocalDate) Value() (driver.Value, error) {
+	return formatRange(r.span(), formatText[LocalDate]), nil
+}
+
+// Scan scans a postgres range literal into *r.
+func (r *RangeLocalDate) Scan(src interface{}) error {
+	s, err := scanRange(src, "RangeLocalDate", parseLocalDate)
+	if err != nil {
+		return err
+	}
+
+	*r = rangeLocalDateFromSpan(s)
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalRangeLocalDate) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalRangeLocalDate) Scan(src interface{}) error {
+	return scanOptional[RangeLocalDate](src, o.Set, o.Unset)
+}
+
+// Value returns o.Get() in the pgvector text format like [1,2.5,3]
+// or nil if o is missing.
+func (o OptionalVector) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range o.val {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(formatFloat32(v))
+	}
+	b.WriteByte(']')
+	return b.String(), nil
+}
+
+// Scan scans a vector in the pgvector text format into *o.
+// A nil src unsets *o.
+func (o *OptionalVector) Scan(src interface{}) error {
+	var str string
+	switch in := src.(type) {
+	case nil:
+		o.Unset()
+		return nil
+	case string:
+		str = in
+	case []byte:
+		str = string(in)
+	default:
+		return scanError(src, "OptionalVector")
+	}
+
+	s := strings.TrimSpace(str)
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return fmt.Errorf("invalid vector: %q", str)
+	}
+
+	vec := []float32{}
+	if body := strings.TrimSpace(s[1 : len(s)-1]); body != "" {
+		for _, element := range strings.Split(body, ",") {
+			v, err := parseFloat32(strings.TrimSpace(element))
+			if err != nil {
+				return fmt.Errorf("invalid vector: %q", str)
+			}
+			vec = append(vec, v)
+		}
+	}
+
+	o.Set(vec)
+	return nil
+}
+
+// Value returns v in the pgvector text format.
+func (v SparseVector) Value() (driver.Value, error) { return v.String(), nil }
+
+// Scan scans a sparse vector in the pgvector text format into *v.
+func (v *SparseVector) Scan(src interface{}) error {
+	var str string
+	switch in := src.(type) {
+	case string:
+		str = in
+	case []byte:
+		str = string(in)
+	default:
+		return scanError(src, "SparseVector")
+	}
+
+	vec, err := parseSparseVector(strings.TrimSpace(str))
+	if err != nil {
+		return err
+	}
+
+	*v = vec
+	return nil
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalSparseVector) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalSparseVector) Scan(src interface{}) error {
+	return scanOptional[SparseVector](src, o.Set, o.Unset)
+}
+
+// Value returns g as hex encoded well-known binary.
+func (g Geometry) Value() (driver.Value, error) {
+	return strings.ToUpper(hex.EncodeToString(g.wkb)), nil
+}
+
+// Scan scans hex encoded or raw well-known binary into *g.
+func (g *Geometry) Scan(src interface{}) error {
+	return scanWKB(src, "Geometry", &g.wkb)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalGeometry) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalGeometry) Scan(src interface{}) error {
+	return scanOptional[Geometry](src, o.Set, o.Unset)
+}
+
+// Value returns g as hex encoded well-known binary.
+func (g Geography) Value() (driver.Value, error) {
+	return strings.ToUpper(hex.EncodeToString(g.wkb)), nil
+}
+
+// Scan scans hex encoded or raw well-known binary into *g.
+func (g *Geography) Scan(src interface{}) error {
+	return scanWKB(src, "Geography", &g.wkb)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalGeography) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalGeography) Scan(src interface{}) error {
+	return scanOptional[Geography](src, o.Set, o.Unset)
+}
+
+// Value returns b as hex encoded well-known binary.
+func (b Box2D) Value() (driver.Value, error) {
+	return strings.ToUpper(hex.EncodeToString(b.wkb)), nil
+}
+
+// Scan scans hex encoded or raw well-known binary into *b.
+func (b *Box2D) Scan(src interface{}) error {
+	return scanWKB(src, "Box2D", &b.wkb)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalBox2D) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalBox2D) Scan(src interface{}) error {
+	return scanOptional[Box2D](src, o.Set, o.Unset)
+}
+
+// Value returns b as hex encoded well-known binary.
+func (b Box3D) Value() (driver.Value, error) {
+	return strings.ToUpper(hex.EncodeToString(b.wkb)), nil
+}
+
+// Scan scans hex encoded or raw well-known binary into *b.
+func (b *Box3D) Scan(src interface{}) error {
+	return scanWKB(src, "Box3D", &b.wkb)
+}
+
+// Value returns o.Get() or nil if o is missing.
+func (o OptionalBox3D) Value() (driver.Value, error) {
+	if !o.isSet {
+		return nil, nil
+	}
+
+	return o.val.Value()
+}
+
+// Scan scans src into *o. A nil src unsets *o.
+func (o *OptionalBox3D) Scan(src interface{}) error {
+	return scanOptional[Box3D](src, o.Set, o.Unset)
+}
+
+// scanWKB scans hex encoded well-known binary, as returned by postgres
+// in text mode, or raw well-known binary into *wkb.
+func scanWKB(src interface{}, name string, wkb *[]byte) error {
+	switch in := src.(type) {
+	case string:
+		data, err := hex.DecodeString(in)
+		if err != nil {
+			return fmt.Errorf("invalid well-known binary: %w", err)
+		}
+		*wkb = data
+	case []byte:
+		// Raw well-known binary starts with a 0 or 1 byte order
+		// marker which is never a hex digit.
+		if data, err := hex.DecodeString(string(in)); err == nil {
+			*wkb = data
+		} else {
+			*wkb = append([]byte(nil), in...)
+		}
+	default:
+		return scanError(src, name)
+	}
+
+	return nil
+}
+
+// formatRange returns s as a postgres range literal.
+// Bounds are quoted if they contain special characters.
+func formatRange[T any](s span[T], format func(T) string) string {
+	if s.empty {
+		return "empty"
+	}
+
+	var b strings.Builder
+	if s.lower.inc {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+
+	if s.lower.isSet {
+		writeRangeBound(&b, format(s.lower.val))
+	}
+	b.WriteByte(',')
+	if s.upper.isSet {
+		writeRangeBound(&b, format(s.upper.val))
+	}
+
+	if s.upper.inc {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+
+	return b.String()
+}
+
+func writeRangeBound(b *strings.Builder, val string) {
+	if val != "" && !strings.ContainsAny(val, ` "\,()[]`) {
+		b.WriteString(val)
+		return
+	}
+
+	b.WriteByte('"')
+	for _, c := range val {
+		if c == '"' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	b.WriteByte('"')
+}
+
+// scanRange parses a postgres range literal like [1,5), (,"2024-01-01"]
+// or empty from src.
+func scanRange[T any](
+	src interface{},
+	name string,
+	parse func(string) (T, error),
+) (span[T], error) {
+	var str string
+	switch in := src.(type) {
+	case string:
+		str = in
+	case []byte:
+		str = string(in)
+	default:
+		return span[T]{}, scanError(src, name)
+	}
+
+	invalid := fmt.Errorf("invalid range literal: %q", str)
+	s := strings.TrimSpace(str)
+	if strings.EqualFold(s, "empty") {
+		return span[T]{empty: true}, nil
+	}
+
+	if len(s) < 3 ||
+		(s[0] != '[' && s[0] != '(') ||
+		(s[len(s)-1] != ']' && s[len(s)-1] != ')') {
+		return span[T]{}, invalid
+	}
+
+	lower, hasLower, rest, ok := readRangeBound(s[1 : len(s)-1])
+	if !ok || rest == "" || rest[0] != ',' {
+		return span[T]{}, invalid
+	}
+
+	upper, hasUpper, rest, ok := readRangeBound(rest[1:])
+	if !ok || rest != "" {
+		return span[T]{}, invalid
+	}
+
+	result := span[T]{
+		lower: bound[T]{isSet: hasLower, inc: s[0] == '['},
+		upper: bound[T]{isSet: hasUpper, inc: s[len(s)-1] == ']'},
+	}
+
+	var err error
+	if hasLower {
+		if result.lower.val, err = parse(lower); err != nil {
+			return span[T]{}, err
+		}
+	}
+
+	if hasUpper {
+		if result.upper.val, err = parse(upper); err != nil {
+			return span[T]{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// readRangeBound reads a possibly quoted bound from the start of s
+// that ends at an unquoted comma or at the end of s. isSet is false
+// if the bound is empty and not quoted.
+func readRangeBound(s string) (val string, isSet bool, rest string, ok bool) {
+	var b strings.Builder
+	quoted := false
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			if i+1 == len(s) {
+				return "", false, "", false
+			}
+			i++
+			b.WriteByte(s[i])
+			isSet = true
+		case c == '"' && quoted && i+1 < len(s) && s[i+1] == '"':
+			i++
+			b.WriteByte('"')
+		case c == '"':
+			quoted = !quoted
+			isSet = true
+		case !quoted && c == ',':
+			return b.String(), isSet, s[i:], true
+		default:
+			b.WriteByte(c)
+			isSet = true
+		}
+	}
+
+	return b.String(), isSet, s[i:], !quoted
+}
+
+func formatInt[T int32 | int64](v T) string {
+	return strconv.FormatInt(int64(v), 10)
+}
+
+func parseInt32(s string) (int32, error) {
+	v, err := strconv.ParseInt(s, 10, 32)
+	return int32(v), err
+}
+
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func formatFloat32(v float32) string {
+	return strconv.FormatFloat(float64(v), 'g', -1, 32)
+}
+
+func parseFloat32(s string) (float32, error) {
+	v, err := strconv.ParseFloat(s, 32)
+	return float32(v), err
+}
+
+func formatFloat64(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func parseFloat64(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+func formatTime(t time.Time) string { return t.Format(time.RFC3339Nano) }
+
+// parseTime parses RFC 3339 or postgres timestamptz output.
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err == nil {
+		return t, nil
+	}
+
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999Z07",
+	} {
+		if t, e := time.Parse(layout, s); e == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// formatText formats types whose MarshalText never returns an error.
+func formatText[T encoding.TextMarshaler](v T) string {
+	data, _ := v.MarshalText()
+	return string(data)
+}
+
+func parseLocalDateTime(s string) (LocalDateTime, error) {
+	var dt LocalDateTime
+	err := dt.Scan(s)
+	return dt, err
+}
+
+func parseLocalDate(s string) (LocalDate, error) {
+	var d LocalDate
+	err := d.UnmarshalText([]byte(s))
+	return d, err
+}
@@ -0,0 +1,414 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLValueRoundTrip(t *testing.T) {
+	id := UUID{1, 2, 3}
+	date := NewLocalDate(2024, time.February, 29)
+	cases := []struct {
+		input    driver.Valuer
+		output   sql.Scanner
+		expected driver.Value
+	}{
+		{id, &UUID{}, "01020300-0000-0000-0000-000000000000"},
+		{
+			NewLocalDateTime(2024, time.February, 29, 1, 2, 3, 4),
+			&LocalDateTime{},
+			"2024-02-29T01:02:03.000004",
+		},
+		{date, &LocalDate{}, "2024-02-29"},
+		{NewLocalTime(1, 2, 3, 4), &LocalTime{}, "01:02:03.000004"},
+		{Duration(3_600_000_001), new(Duration), "PT1H0.000001S"},
+		{NewRelativeDuration(1, 2, 3), &RelativeDuration{}, "P1M2DT0.000003S"},
+		{NewDateDuration(1, 2), &DateDuration{}, "P1M2D"},
+		{Memory(2048), new(Memory), "2KiB"},
+		{NewDecimalFromInt64(-12), &Decimal{}, "-12"},
+		{NewOptionalUUID(id), &OptionalUUID{}, id.String()},
+		{OptionalUUID{}, &OptionalUUID{}, nil},
+		{NewOptionalLocalDate(date), &OptionalLocalDate{}, "2024-02-29"},
+		{OptionalLocalDate{}, &OptionalLocalDate{}, nil},
+		{NewOptionalMemory(1), &OptionalMemory{}, "1B"},
+		{NewOptionalStr("abc"), &OptionalStr{}, "abc"},
+		{OptionalStr{}, &OptionalStr{}, nil},
+		{NewOptionalBytes([]byte{1}), &OptionalBytes{}, []byte{1}},
+		{NewOptionalBool(true), &OptionalBool{}, true},
+		{NewOptionalInt16(-1), &OptionalInt16{}, int64(-1)},
+		{NewOptionalInt32(2), &OptionalInt32{}, int64(2)},
+		{NewOptionalInt64(3), &OptionalInt64{}, int64(3)},
+		{NewOptionalFloat32(0.5), &OptionalFloat32{}, float64(0.5)},
+		{NewOptionalFloat64(1.5), &OptionalFloat64{}, float64(1.5)},
+		{
+			NewOptionalBigInt(big.NewInt(-5)),
+			&OptionalBigInt{},
+			"-5",
+		},
+		{OptionalBigInt{}, &OptionalBigInt{}, nil},
+		{NewOpt(int32(7)), &Opt[int32]{}, int64(7)},
+		{NewOpt(Memory(1)), &Opt[Memory]{}, "1B"},
+		{Opt[string]{}, &Opt[string]{}, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%T", c.input), func(t *testing.T) {
+			val, err := c.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, val)
+
+			require.NoError(t, c.output.Scan(val))
+			out := reflect.ValueOf(c.output).Elem().Interface()
+			assert.Equal(t, c.input, out)
+		})
+	}
+}
+
+func TestSQLScan(t *testing.T) {
+	ts := time.Date(2024, time.February, 29, 1, 2, 3, 4_000, time.UTC)
+
+	var id UUID
+	require.NoError(t, id.Scan([]byte{1, 2, 3, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0}))
+	assert.Equal(t, UUID{1, 2, 3}, id)
+
+	var dt LocalDateTime
+	require.NoError(t, dt.Scan(ts))
+	assert.Equal(t, NewLocalDateTime(2024, time.February, 29, 1, 2, 3, 4), dt)
+	require.NoError(t, dt.Scan([]byte("2024-02-29 01:02:03")))
+	assert.Equal(t, NewLocalDateTime(2024, time.February, 29, 1, 2, 3, 0), dt)
+
+	var date LocalDate
+	require.NoError(t, date.Scan(ts))
+	assert.Equal(t, NewLocalDate(2024, time.February, 29), date)
+
+	var lt LocalTime
+	require.NoError(t, lt.Scan(ts))
+	assert.Equal(t, NewLocalTime(1, 2, 3, 4), lt)
+
+	var d Duration
+	require.NoError(t, d.Scan(int64(5)))
+	assert.Equal(t, Duration(5), d)
+
+	var m Memory
+	require.NoError(t, m.Scan(int64(5)))
+	assert.Equal(t, Memory(5), m)
+
+	var dec Decimal
+	require.NoError(t, dec.Scan(1.25))
+	assert.Equal(t, "1.25", dec.String())
+
+	var bigint OptionalBigInt
+	require.NoError(t, bigint.Scan(int64(5)))
+	assert.Equal(t, NewOptionalBigInt(big.NewInt(5)), bigint)
+
+	var opt Opt[string]
+	require.NoError(t, opt.Scan([]byte("abc")))
+	assert.Equal(t, NewOpt("abc"), opt)
+
+	optDate := NewOptionalLocalDate(date)
+	require.NoError(t, optDate.Scan(nil))
+	assert.Equal(t, OptionalLocalDate{}, optDate)
+}
+
+func TestSQLScanErrors(t *testing.T) {
+	var id UUID
+	assert.EqualError(t, id.Scan(int64(1)),
+		"cannot scan int64 into edgedb.UUID")
+
+	var m Memory
+	assert.EqualError(t, m.Scan(true), "cannot scan bool into edgedb.Memory")
+
+	var bigint OptionalBigInt
+	assert.EqualError(t, bigint.Scan("1.5"),
+		`cannot scan "1.5" into edgedb.OptionalBigInt`)
+
+	var small Opt[int8]
+	assert.EqualError(t, small.Scan(int64(1_000)),
+		"cannot scan int64 into edgedb.Opt[int8]")
+
+	var str Opt[string]
+	assert.EqualError(t, str.Scan(int64(1)),
+		"cannot scan int64 into edgedb.Opt[string]")
+}
+
+func TestSQLScanCopiesBytes(t *testing.T) {
+	src := []byte("abc")
+
+	var b Opt[[]byte]
+	require.NoError(t, b.Scan(src))
+	var s Opt[string]
+	require.NoError(t, s.Scan(src))
+	var i Opt[interface{}]
+	require.NoError(t, i.Scan(src))
+
+	// Drivers reuse the buffers they pass to Scan.
+	copy(src, "xyz")
+	assert.Equal(t, NewOpt([]byte("abc")), b)
+	assert.Equal(t, NewOpt("abc"), s)
+	assert.Equal(t, NewOpt[interface{}]([]byte("abc")), i)
+}
+
+func TestDurationScanPostgresInterval(t *testing.T) {
+	samples := []struct {
+		input    string
+		expected Duration
+	}{
+		{"00:00:00", 0},
+		{"01:00:00", Duration(usecsPerHour)},
+		{"-00:00:01.5", -1_500_000},
+		{"00:00:00.000001", 1},
+		{"1 day", Duration(24 * usecsPerHour)},
+		{"3 days 02:00:00", Duration(74 * usecsPerHour)},
+		{"-1 days +02:03:04", Duration(-24*usecsPerHour + 7_384_000_000)},
+		{"100:00:00", Duration(100 * usecsPerHour)},
+	}
+
+	for _, s := range samples {
+		t.Run(s.input, func(t *testing.T) {
+			var d Duration
+			require.NoError(t, d.Scan(s.input))
+			assert.Equal(t, s.expected, d)
+
+			require.NoError(t, d.Scan([]byte(s.input)))
+			assert.Equal(t, s.expected, d)
+		})
+	}
+
+	invalid := []string{
+		"1 mon",
+		"1 year 2 mons",
+		"1 day 02:00",
+		"02:00:00 1 day",
+		"00:60:00",
+		"00:00:00.1234567",
+		"1 day +",
+		"99999999999 days",
+	}
+
+	for _, input := range invalid {
+		t.Run(input, func(t *testing.T) {
+			var d Duration
+			assert.Error(t, d.Scan(input))
+		})
+	}
+}
+
+func TestSQLValueRoundTripRangesVectorsAndPostGIS(t *testing.T) {
+	ts := time.Date(2024, time.February, 29, 1, 2, 3, 4_000, time.UTC)
+	ldt := NewLocalDateTime(2024, time.February, 29, 1, 2, 3, 4)
+	date := NewLocalDate(2024, time.February, 29)
+	int32Range := NewRangeInt32(
+		NewOptionalInt32(1), NewOptionalInt32(5), true, false)
+	sparse := NewSparseVectorFromSlice([]float32{0, 1.5, 0})
+	wkb := NewGeometry(Point{1, 2}).WKB()
+	hexWKB := "0101000000000000000000F03F0000000000000040"
+
+	cases := []struct {
+		input    driver.Valuer
+		output   sql.Scanner
+		expected driver.Value
+	}{
+		{int32Range, &RangeInt32{}, "[1,5)"},
+		{RangeInt32{empty: true}, &RangeInt32{}, "empty"},
+		{
+			NewOptionalRangeInt32(int32Range),
+			&OptionalRangeInt32{},
+			"[1,5)",
+		},
+		{OptionalRangeInt32{}, &OptionalRangeInt32{}, nil},
+		{
+			NewRangeInt64(OptionalInt64{}, NewOptionalInt64(3), false, true),
+			&RangeInt64{},
+			"(,4)",
+		},
+		{
+			NewOptionalRangeInt64(NewRangeInt64(
+				NewOptionalInt64(-3), OptionalInt64{}, true, false)),
+			&OptionalRangeInt64{},
+			"[-3,)",
+		},
+		{
+			NewRangeFloat32(
+				NewOptionalFloat32(0.5), NewOptionalFloat32(1.5), true, true),
+			&RangeFloat32{},
+			"[0.5,1.5]",
+		},
+		{
+			NewOptionalRangeFloat32(NewRangeFloat32(
+				OptionalFloat32{}, OptionalFloat32{}, false, false)),
+			&OptionalRangeFloat32{},
+			"(,)",
+		},
+		{
+			NewRangeFloat64(
+				NewOptionalFloat64(-1e100), NewOptionalFloat64(2),
+				false, true),
+			&RangeFloat64{},
+			"(-1e+100,2]",
+		},
+		{OptionalRangeFloat64{}, &OptionalRangeFloat64{}, nil},
+		{
+			NewRangeDateTime(
+				NewOptionalDateTime(ts), OptionalDateTime{}, true, false),
+			&RangeDateTime{},
+			"[2024-02-29T01:02:03.000004Z,)",
+		},
+		{
+			NewOptionalRangeDateTime(NewRangeDateTime(
+				OptionalDateTime{}, NewOptionalDateTime(ts), false, false)),
+			&OptionalRangeDateTime{},
+			"(,2024-02-29T01:02:03.000004Z)",
+		},
+		{
+			NewRangeLocalDateTime(
+				NewOptionalLocalDateTime(ldt), OptionalLocalDateTime{},
+				true, false),
+			&RangeLocalDateTime{},
+			"[2024-02-29T01:02:03.000004,)",
+		},
+		{
+			NewOptionalRangeLocalDateTime(RangeLocalDateTime{empty: true}),
+			&OptionalRangeLocalDateTime{},
+			"empty",
+		},
+		{
+			NewRangeLocalDate(
+				NewOptionalLocalDate(date), OptionalLocalDate{}, true, false),
+			&RangeLocalDate{},
+			"[2024-02-29,)",
+		},
+		{
+			NewOptionalRangeLocalDate(NewRangeLocalDate(
+				NewOptionalLocalDate(date), NewOptionalLocalDate(date),
+				true, true)),
+			&OptionalRangeLocalDate{},
+			"[2024-02-29,2024-03-01)",
+		},
+		{
+			NewOptionalVector([]float32{1, 2.5, -3}),
+			&OptionalVector{},
+			"[1,2.5,-3]",
+		},
+		{NewOptionalVector([]float32{}), &OptionalVector{}, "[]"},
+		{OptionalVector{}, &OptionalVector{}, nil},
+		{sparse, &SparseVector{}, "{2:1.5}/3"},
+		{
+			NewOptionalSparseVector(sparse),
+			&OptionalSparseVector{},
+			"{2:1.5}/3",
+		},
+		{OptionalSparseVector{}, &OptionalSparseVector{}, nil},
+		{NewGeometryFromWKB(wkb), &Geometry{}, hexWKB},
+		{
+			NewOptionalGeometry(NewGeometryFromWKB(wkb)),
+			&OptionalGeometry{},
+			hexWKB,
+		},
+		{NewGeographyFromWKB(wkb), &Geography{}, hexWKB},
+		{OptionalGeography{}, &OptionalGeography{}, nil},
+		{NewBox2DFromWKB(wkb), &Box2D{}, hexWKB},
+		{
+			NewOptionalBox2D(NewBox2DFromWKB(wkb)),
+			&OptionalBox2D{},
+			hexWKB,
+		},
+		{NewBox3DFromWKB(wkb), &Box3D{}, hexWKB},
+		{OptionalBox3D{}, &OptionalBox3D{}, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%T %v", c.input, c.expected), func(t *testing.T) {
+			val, err := c.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, val)
+
+			require.NoError(t, c.output.Scan(val))
+			out := reflect.ValueOf(c.output).Elem().Interface()
+			assert.Equal(t, c.input, out)
+		})
+	}
+}
+
+func TestSQLScanPostgresRanges(t *testing.T) {
+	var r RangeInt64
+	require.NoError(t, r.Scan([]byte("[1,6)")))
+	assert.Equal(t, NewRangeInt64(
+		NewOptionalInt64(1), NewOptionalInt64(5), true, true), r)
+
+	require.NoError(t, r.Scan("EMPTY"))
+	assert.True(t, r.Empty())
+
+	var dt RangeDateTime
+	require.NoError(t, dt.Scan(
+		`["2024-02-29 01:02:03.000004+00","2024-03-01 00:00:00+01")`))
+	lower, ok := dt.Lower().Get()
+	require.True(t, ok)
+	assert.True(t, lower.Equal(
+		time.Date(2024, time.February, 29, 1, 2, 3, 4_000, time.UTC)))
+	upper, ok := dt.Upper().Get()
+	require.True(t, ok)
+	assert.True(t, upper.Equal(
+		time.Date(2024, time.February, 29, 23, 0, 0, 0, time.UTC)))
+
+	var ldt RangeLocalDateTime
+	require.NoError(t, ldt.Scan(`["2024-02-29 01:02:03",)`))
+	assert.Equal(t, NewRangeLocalDateTime(
+		NewOptionalLocalDateTime(
+			NewLocalDateTime(2024, time.February, 29, 1, 2, 3, 0)),
+		OptionalLocalDateTime{}, true, false), ldt)
+
+	invalid := []string{"", "[1,2", "1,2)", "[1)", `["1,2)`, "[1,2,3)"}
+	for _, input := range invalid {
+		assert.EqualError(t, r.Scan(input),
+			fmt.Sprintf("invalid range literal: %q", input))
+	}
+
+	assert.EqualError(t, r.Scan(int64(1)),
+		"cannot scan int64 into edgedb.RangeInt64")
+	assert.Error(t, r.Scan("[a,2)"))
+}
+
+func TestSQLScanVectorAndWKBErrors(t *testing.T) {
+	var v OptionalVector
+	assert.EqualError(t, v.Scan("1,2"), `invalid vector: "1,2"`)
+	assert.EqualError(t, v.Scan("[1,a]"), `invalid vector: "[1,a]"`)
+
+	var sparse SparseVector
+	assert.EqualError(t, sparse.Scan(int64(1)),
+		"cannot scan int64 into edgedb.SparseVector")
+
+	// Raw well-known binary is accepted as well as hex.
+	wkb := NewGeometry(Point{1, 2}).WKB()
+	var g Geometry
+	require.NoError(t, g.Scan(wkb))
+	assert.Equal(t, wkb, g.WKB())
+
+	assert.Error(t, g.Scan("0G"))
+	assert.EqualError(t, g.Scan(int64(1)),
+		"cannot scan int64 into edgedb.Geometry")
+}
@@ -0,0 +1,305 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NewOptionalVector is a convenience function for creating an
+// OptionalVector with its value set to v.
+func NewOptionalVector(v []float32) OptionalVector {
+	o := OptionalVector{}
+	o.Set(v)
+	return o
+}
+
+// OptionalVector is an optional ext::pgvector::vector or
+// ext::pgvector::halfvec. Optional types must be used for out parameters
+// when a shape field is not required.
+type OptionalVector struct {
+	val   []float32
+	isSet bool
+}
+
+// Get returns the value and a boolean indicating if the value is present.
+func (o OptionalVector) Get() ([]float32, bool) { return o.val, o.isSet }
+
+// Set sets the value.
+func (o *OptionalVector) Set(val []float32) {
+	if val == nil {
+		o.Unset()
+		return
+	}
+
+	o.val = val
+	o.isSet = true
+}
+
+// Unset marks the value as missing.
+func (o *OptionalVector) Unset() {
+	o.val = nil
+	o.isSet = false
+}
+
+// MarshalJSON returns o marshaled as json.
+func (o OptionalVector) MarshalJSON() ([]byte, error) {
+	if o.isSet {
+		return json.Marshal(o.val)
+	}
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON unmarshals bytes into *o.
+func (o *OptionalVector) UnmarshalJSON(bytes []byte) error {
+	if bytes[0] == 0x6e { // null
+		o.Unset()
+		return nil
+	}
+
+	if err := json.Unmarshal(bytes, &o.val); err != nil {
+		return err
+	}
+	o.isSet = true
+
+	return nil
+}
+
+// NewSparseVector returns a SparseVector with dim dimensions.
+// elements maps zero based indices to values. Zero values are dropped.
+func NewSparseVector(
+	dim int,
+	elements map[int]float32,
+) (SparseVector, error) {
+	if dim < 0 {
+		return SparseVector{}, fmt.Errorf(
+			"sparse vector dimensions must be positive got %v", dim)
+	}
+
+	v := SparseVector{dim: dim}
+	for i, val := range elements {
+		if i < 0 || i >= dim {
+			return SparseVector{}, fmt.Errorf(
+				"sparse vector index %v out of range for %v dimensions",
+				i, dim)
+		}
+
+		if val != 0 {
+			v.indices = append(v.indices, i)
+		}
+	}
+
+	sort.Ints(v.indices)
+	v.values = make([]float32, len(v.indices))
+	for j, i := range v.indices {
+		v.values[j] = elements[i]
+	}
+
+	return v, nil
+}
+
+// NewSparseVectorFromSlice returns a SparseVector
+// with the same elements as vec.
+func NewSparseVectorFromSlice(vec []float32) SparseVector {
+	v := SparseVector{dim: len(vec)}
+	for i, val := range vec {
+		if val != 0 {
+			v.indices = append(v.indices, i)
+			v.values = append(v.values, val)
+		}
+	}
+
+	return v
+}
+
+// SparseVector is an ext::pgvector::sparsevec.
+// Only the non zero elements are stored.
+type SparseVector struct {
+	dim     int
+	indices []int
+	values  []float32
+}
+
+// Dim returns the number of dimensions.
+func (v SparseVector) Dim() int { return v.dim }
+
+// Indices returns the zero based indices of the non zero elements
+// in ascending order.
+func (v SparseVector) Indices() []int {
+	return append([]int(nil), v.indices...)
+}
+
+// Values returns the non zero elements
+// in the same order as the indices returned by Indices().
+func (v SparseVector) Values() []float32 {
+	return append([]float32(nil), v.values...)
+}
+
+// Slice returns v as a dense vector.
+func (v SparseVector) Slice() []float32 {
+	vec := make([]float32, v.dim)
+	for j, i := range v.indices {
+		vec[i] = v.values[j]
+	}
+
+	return vec
+}
+
+// String returns v in the pgvector text format.
+// Indices in the text format are one based.
+func (v SparseVector) String() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for j, i := range v.indices {
+		if j > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(i + 1))
+		b.WriteByte(':')
+		b.WriteString(strconv.FormatFloat(float64(v.values[j]), 'g', -1, 32))
+	}
+	b.WriteString("}/")
+	b.WriteString(strconv.Itoa(v.dim))
+	return b.String()
+}
+
+// MarshalJSON returns v marshaled as a json string
+// in the pgvector text format.
+func (v SparseVector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// UnmarshalJSON unmarshals a json string
+// in the pgvector text format into *v.
+func (v *SparseVector) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	vec, err := parseSparseVector(s)
+	if err != nil {
+		return err
+	}
+
+	*v = vec
+	return nil
+}
+
+// parseSparseVector parses s in the pgvector text format
+// e.g. {1:1.5,3:2}/5
+func parseSparseVector(s string) (SparseVector, error) {
+	invalid := fmt.Errorf("invalid sparse vector: %q", s)
+
+	i := strings.LastIndex(s, "}/")
+	if len(s) < 3 || s[0] != '{' || i < 0 {
+		return SparseVector{}, invalid
+	}
+
+	dim, err := strconv.Atoi(s[i+2:])
+	if err != nil {
+		return SparseVector{}, invalid
+	}
+
+	elements := make(map[int]float32)
+	if body := s[1:i]; body != "" {
+		for _, element := range strings.Split(body, ",") {
+			idx, val, ok := strings.Cut(element, ":")
+			if !ok {
+				return SparseVector{}, invalid
+			}
+
+			index, err := strconv.Atoi(strings.TrimSpace(idx))
+			if err != nil {
+				return SparseVector{}, invalid
+			}
+
+			value, err := strconv.ParseFloat(strings.TrimSpace(val), 32)
+			if err != nil {
+				return SparseVector{}, invalid
+			}
+
+			// Indices in the text format are one based.
+			if _, ok := elements[index-1]; ok {
+				return SparseVector{}, fmt.Errorf(
+					"sparse vector index %v is repeated", index)
+			}
+			elements[index-1] = float32(value)
+		}
+	}
+
+	return NewSparseVector(dim, elements)
+}
+
+// NewOptionalSparseVector is a convenience function for creating an
+// OptionalSparseVector with its value set to v.
+func NewOptionalSparseVector(v SparseVector) OptionalSparseVector {
+	o := OptionalSparseVector{}
+	o.Set(v)
+	return o
+}
+
+// OptionalSparseVector is an optional SparseVector. Optional types must be
+// used for out parameters when a shape field is not required.
+type OptionalSparseVector struct {
+	val   SparseVector
+	isSet bool
+}
+
+// Get returns the value and a boolean indicating if the value is present.
+func (o OptionalSparseVector) Get() (SparseVector, bool) {
+	return o.val, o.isSet
+}
+
+// Set sets the value.
+func (o *OptionalSparseVector) Set(val SparseVector) {
+	o.val = val
+	o.isSet = true
+}
+
+// Unset marks the value as missing.
+func (o *OptionalSparseVector) Unset() {
+	o.val = SparseVector{}
+	o.isSet = false
+}
+
+// MarshalJSON returns o marshaled as json.
+func (o OptionalSparseVector) MarshalJSON() ([]byte, error) {
+	if o.isSet {
+		return json.Marshal(o.val)
+	}
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON unmarshals bytes into *o.
+func (o *OptionalSparseVector) UnmarshalJSON(bytes []byte) error {
+	if bytes[0] == 0x6e { // null
+		o.Unset()
+		return nil
+	}
+
+	if err := json.Unmarshal(bytes, &o.val); err != nil {
+		return err
+	}
+	o.isSet = true
+
+	return nil
+}
@@ -0,0 +1,112 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSparseVector(t *testing.T) {
+	v, err := NewSparseVector(5, map[int]float32{4: -2, 1: 1.5, 2: 0})
+	require.NoError(t, err)
+	assert.Equal(t, 5, v.Dim())
+	assert.Equal(t, []int{1, 4}, v.Indices())
+	assert.Equal(t, []float32{1.5, -2}, v.Values())
+	assert.Equal(t, []float32{0, 1.5, 0, 0, -2}, v.Slice())
+	assert.Equal(t, "{2:1.5,5:-2}/5", v.String())
+	assert.Equal(t, v, NewSparseVectorFromSlice(v.Slice()))
+
+	_, err = NewSparseVector(3, map[int]float32{3: 1})
+	assert.EqualError(t, err,
+		"sparse vector index 3 out of range for 3 dimensions")
+
+	_, err = NewSparseVector(-1, nil)
+	assert.EqualError(t, err,
+		"sparse vector dimensions must be positive got -1")
+}
+
+func TestOptionalVectorJSON(t *testing.T) {
+	data, err := json.Marshal(NewOptionalVector([]float32{1, 0.5}))
+	require.NoError(t, err)
+	assert.Equal(t, "[1,0.5]", string(data))
+
+	var o OptionalVector
+	require.NoError(t, json.Unmarshal([]byte("[2,3]"), &o))
+	assert.Equal(t, NewOptionalVector([]float32{2, 3}), o)
+
+	require.NoError(t, json.Unmarshal([]byte("null"), &o))
+	assert.Equal(t, OptionalVector{}, o)
+}
+
+func TestSparseVectorJSON(t *testing.T) {
+	v, err := NewSparseVector(5, map[int]float32{1: 1.5, 4: -2})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `"{2:1.5,5:-2}/5"`, string(data))
+
+	var result SparseVector
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, v, result)
+
+	require.NoError(t, json.Unmarshal([]byte(`"{}/3"`), &result))
+	assert.Equal(t, 3, result.Dim())
+	assert.Equal(t, []int(nil), result.Indices())
+
+	require.NoError(t, json.Unmarshal([]byte(`"{3: 1, 1: 2}/4"`), &result))
+	assert.Equal(t, []float32{2, 0, 1, 0}, result.Slice())
+
+	invalid := []string{"", "{}", "{1:1}", "1:1/2", "{1}/2", "{a:1}/2"}
+	for _, input := range invalid {
+		data, err := json.Marshal(input)
+		require.NoError(t, err)
+		err = json.Unmarshal(data, &result)
+		assert.EqualError(t, err,
+			fmt.Sprintf("invalid sparse vector: %q", input))
+	}
+
+	err = json.Unmarshal([]byte(`"{3:1}/2"`), &result)
+	assert.EqualError(t, err,
+		"sparse vector index 2 out of range for 2 dimensions")
+
+	err = json.Unmarshal([]byte(`"{1:1,1:2}/2"`), &result)
+	assert.EqualError(t, err, "sparse vector index 1 is repeated")
+}
+
+func TestOptionalSparseVectorJSON(t *testing.T) {
+	v := NewSparseVectorFromSlice([]float32{0, 1})
+	data, err := json.Marshal(NewOptionalSparseVector(v))
+	require.NoError(t, err)
+	assert.Equal(t, `"{2:1}/2"`, string(data))
+
+	data, err = json.Marshal(OptionalSparseVector{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+
+	var o OptionalSparseVector
+	require.NoError(t, json.Unmarshal([]byte(`"{2:1}/2"`), &o))
+	assert.Equal(t, NewOptionalSparseVector(v), o)
+
+	require.NoError(t, json.Unmarshal([]byte("null"), &o))
+	assert.Equal(t, OptionalSparseVector{}, o)
+}
@@ -0,0 +1,445 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedbtypes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Geometry type codes used by well-known binary.
+// https://libgeos.org/specifications/wkb/
+const (
+	wkbPoint      uint32 = 1
+	wkbLineString uint32 = 2
+	wkbPolygon    uint32 = 3
+
+	// extended well-known binary flags used by PostGIS
+	ewkbZ    uint32 = 0x80000000
+	ewkbM    uint32 = 0x40000000
+	ewkbSRID uint32 = 0x20000000
+)
+
+var errTruncatedWKB = errors.New("malformed wkb: unexpected end of data")
+
+// Shape is a two dimensional geometric shape.
+// It is implemented by Point, LineString and Polygon.
+type Shape interface {
+	appendWKB(b []byte) []byte
+	appendWKT(b []byte) []byte
+}
+
+// Point is a two dimensional point.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// LineString is a sequence of connected points.
+type LineString []Point
+
+// Polygon is a sequence of linear rings. The first ring is the exterior
+// boundary and the rest are holes. Each ring's last point must equal its
+// first point.
+type Polygon []LineString
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendWKBHeader(b []byte, typ uint32) []byte {
+	b = append(b, 1) // little endian
+	return appendUint32(b, typ)
+}
+
+func appendWKBPoints(b []byte, points []Point) []byte {
+	b = appendUint32(b, uint32(len(points)))
+	for _, p := range points {
+		b = appendWKBCoords(b, p)
+	}
+	return b
+}
+
+func appendWKBCoords(b []byte, p Point) []byte {
+	b = appendUint64(b, math.Float64bits(p.X))
+	return appendUint64(b, math.Float64bits(p.Y))
+}
+
+func (p Point) appendWKB(b []byte) []byte {
+	return appendWKBCoords(appendWKBHeader(b, wkbPoint), p)
+}
+
+func (l LineString) appendWKB(b []byte) []byte {
+	return appendWKBPoints(appendWKBHeader(b, wkbLineString), l)
+}
+
+func (p Polygon) appendWKB(b []byte) []byte {
+	b = appendWKBHeader(b, wkbPolygon)
+	b = appendUint32(b, uint32(len(p)))
+	for _, ring := range p {
+		b = appendWKBPoints(b, ring)
+	}
+	return b
+}
+
+func appendWKTCoords(b []byte, p Point) []byte {
+	b = strconv.AppendFloat(b, p.X, 'g', -1, 64)
+	b = append(b, ' ')
+	return strconv.AppendFloat(b, p.Y, 'g', -1, 64)
+}
+
+func appendWKTPoints(b []byte, points []Point) []byte {
+	if len(points) == 0 {
+		return append(b, " EMPTY"...)
+	}
+
+	b = append(b, '(')
+	for i, p := range points {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendWKTCoords(b, p)
+	}
+	return append(b, ')')
+}
+
+func (p Point) appendWKT(b []byte) []byte {
+	b = append(b, "POINT("...)
+	return append(appendWKTCoords(b, p), ')')
+}
+
+func (l LineString) appendWKT(b []byte) []byte {
+	return appendWKTPoints(append(b, "LINESTRING"...), l)
+}
+
+func (p Polygon) appendWKT(b []byte) []byte {
+	b = append(b, "POLYGON"...)
+	if len(p) == 0 {
+		return append(b, " EMPTY"...)
+	}
+
+	b = append(b, '(')
+	for i, ring := range p {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendWKTPoints(b, ring)
+	}
+	return append(b, ')')
+}
+
+// wkbReader decodes well-known binary.
+type wkbReader struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if len(r.buf) < 4 {
+		return 0, errTruncatedWKB
+	}
+
+	v := r.order.Uint32(r.buf)
+	r.buf = r.buf[4:]
+	return v, nil
+}
+
+func (r *wkbReader) point() (Point, error) {
+	if len(r.buf) < 16 {
+		return Point{}, errTruncatedWKB
+	}
+
+	p := Point{
+		X: math.Float64frombits(r.order.Uint64(r.buf)),
+		Y: math.Float64frombits(r.order.Uint64(r.buf[8:])),
+	}
+	r.buf = r.buf[16:]
+	return p, nil
+}
+
+func (r *wkbReader) points() ([]Point, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(n)*16 > uint64(len(r.buf)) {
+		return nil, errTruncatedWKB
+	}
+
+	points := make([]Point, n)
+	for i := range points {
+		points[i], _ = r.point()
+	}
+	return points, nil
+}
+
+// decodeWKB decodes a Point, LineString or Polygon from well-known binary
+// or PostGIS extended well-known binary. The SRID, if any, is ignored.
+func decodeWKB(data []byte) (Shape, error) {
+	if len(data) < 5 {
+		return nil, errTruncatedWKB
+	}
+
+	r := wkbReader{buf: data[1:]}
+	switch data[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, fmt.Errorf(
+			"malformed wkb: invalid byte order %v", data[0])
+	}
+
+	typ, _ := r.uint32()
+	hasSRID := typ&ewkbSRID != 0
+	typ &^= ewkbSRID
+
+	// ISO wkb adds 1000, 2000 or 3000 to the type for Z, M and ZM.
+	if typ&(ewkbZ|ewkbM) != 0 || typ > 1000 {
+		return nil, errors.New(
+			"unsupported wkb: only two dimensional shapes are supported")
+	}
+
+	if hasSRID {
+		if _, err := r.uint32(); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		shape Shape
+		err   error
+	)
+
+	switch typ {
+	case wkbPoint:
+		shape, err = r.point()
+	case wkbLineString:
+		var points []Point
+		points, err = r.points()
+		shape = LineString(points)
+	case wkbPolygon:
+		var n uint32
+		n, err = r.uint32()
+		if err != nil {
+			return nil, err
+		}
+
+		polygon := Polygon{}
+		for i := uint32(0); i < n; i++ {
+			var ring []Point
+			ring, err = r.points()
+			if err != nil {
+				return nil, err
+			}
+			polygon = append(polygon, ring)
+		}
+		shape = polygon
+	default:
+		return nil, fmt.Errorf("unsupported wkb geometry type %v", typ)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(r.buf) != 0 {
+		return nil, errors.New("malformed wkb: unexpected trailing data")
+	}
+
+	return shape, nil
+}
+
+// wktParser parses well-known text.
+type wktParser struct {
+	input string
+	pos   int
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *wktParser) error() error {
+	return fmt.Errorf("invalid wkt: %q", p.input)
+}
+
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && unicode.IsLetter(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return strings.ToUpper(p.input[start:p.pos])
+}
+
+func (p *wktParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) float() (float64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) &&
+		strings.IndexByte("+-.0123456789eE", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+
+	f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, p.error()
+	}
+	return f, nil
+}
+
+func (p *wktParser) coords() (Point, error) {
+	x, err := p.float()
+	if err != nil {
+		return Point{}, err
+	}
+
+	y, err := p.float()
+	if err != nil {
+		return Point{}, err
+	}
+
+	return Point{X: x, Y: y}, nil
+}
+
+// empty consumes the EMPTY keyword if it is next.
+func (p *wktParser) empty() bool {
+	pos := p.pos
+	if p.word() == "EMPTY" {
+		return true
+	}
+	p.pos = pos
+	return false
+}
+
+func (p *wktParser) points() ([]Point, error) {
+	if p.empty() {
+		return []Point{}, nil
+	}
+
+	if !p.consume('(') {
+		return nil, p.error()
+	}
+
+	var points []Point
+	for {
+		point, err := p.coords()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+
+		if p.consume(')') {
+			return points, nil
+		}
+		if !p.consume(',') {
+			return nil, p.error()
+		}
+	}
+}
+
+func (p *wktParser) polygon() (Polygon, error) {
+	if p.empty() {
+		return Polygon{}, nil
+	}
+
+	if !p.consume('(') {
+		return nil, p.error()
+	}
+
+	var polygon Polygon
+	for {
+		ring, err := p.points()
+		if err != nil {
+			return nil, err
+		}
+		polygon = append(polygon, ring)
+
+		if p.consume(')') {
+			return polygon, nil
+		}
+		if !p.consume(',') {
+			return nil, p.error()
+		}
+	}
+}
+
+// parseWKT parses a POINT, LINESTRING or POLYGON from well-known text.
+func parseWKT(s string) (Shape, error) {
+	p := wktParser{input: s}
+
+	var (
+		shape Shape
+		err   error
+	)
+
+	switch p.word() {
+	case "POINT":
+		if !p.consume('(') {
+			return nil, p.error()
+		}
+		shape, err = p.coords()
+		if err == nil && !p.consume(')') {
+			err = p.error()
+		}
+	case "LINESTRING":
+		var points []Point
+		points, err = p.points()
+		shape = LineString(points)
+	case "POLYGON":
+		shape, err = p.polygon()
+	default:
+		return nil, fmt.Errorf("unsupported wkt: %q", s)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos != len(p.input) {
+		return nil, p.error()
+	}
+
+	return shape, nil
+}
@@ -16,6 +16,8 @@
 
 package internal
 
+import "math/big"
+
 // ProtocolVersion represents an EdgeDB protocol version
 type ProtocolVersion struct {
 	Major uint16
@@ -54,3 +56,10 @@ func (v ProtocolVersion) LT(other ProtocolVersion) bool {
 		return v.Minor < other.Minor
 	}
 }
+
+var bigTen = big.NewInt(10)
+
+// Pow10 returns 10**n.
+func Pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
@@ -29,8 +29,10 @@ func fieldByTag(t reflect.Type, name string) (reflect.StructField, bool) {
 			return field, true
 		case "$inline":
 			if f, ok := fieldByTag(field.Type, name); ok {
-				// Accumulate offsets from nested paths.
+				// Accumulate offsets and indices from nested paths.
 				f.Offset += field.Offset
+				index := append([]int(nil), field.Index...)
+				f.Index = append(index, f.Index...)
 				return f, true
 			}
 		}
@@ -0,0 +1,41 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import "github.com/edgedb/edgedb-go/internal/edgedbtypes"
+
+// Opt is an optional T. Like the other optional types it is used for shape
+// fields that are not required and for optional query arguments, but it
+// works with any T that can be decoded, including structs and types with
+// custom unmarshalers.
+//
+//	type User struct {
+//	    Name  string                  `edgedb:"name"`
+//	    Email edgedb.Opt[string]      `edgedb:"email"`
+//	    Boss  edgedb.Opt[UserSummary] `edgedb:"boss"`
+//	}
+type Opt[T any] struct {
+	// Opt is embedded because generic type aliases
+	// are not supported by go 1.18.
+	edgedbtypes.Opt[T]
+}
+
+// NewOpt is a convenience function for creating an Opt with its value set
+// to v.
+func NewOpt[T any](v T) Opt[T] {
+	return Opt[T]{edgedbtypes.NewOpt(v)}
+}
@@ -0,0 +1,54 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb_test
+
+import (
+	"log"
+
+	edgedb "github.com/edgedb/edgedb-go"
+)
+
+// Opt can be used for any type, including structs.
+func ExampleOpt() {
+	type Friend struct {
+		Name string `edgedb:"name"`
+	}
+
+	type User struct {
+		Name     string             `edgedb:"name"`
+		Nickname edgedb.Opt[string] `edgedb:"nickname"`
+		Friend   edgedb.Opt[Friend] `edgedb:"best_friend"`
+	}
+
+	var users []User
+	err := client.Query(
+		ctx,
+		`SELECT User { name, nickname, best_friend: { name } }
+		FILTER .nickname ?= <optional str>$0`,
+		&users,
+		edgedb.NewOpt("Al"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, user := range users {
+		if friend, ok := user.Friend.Get(); ok {
+			log.Println(user.Name, friend.Name)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// QueryAll runs a query on exec and returns the results.
+//
+//	users, err := edgedb.QueryAll[User](ctx, client, "SELECT User {name}")
+func QueryAll[T any](
+	ctx context.Context,
+	exec Executor,
+	cmd string,
+	args ...any,
+) ([]T, error) {
+	var result []T
+	if err := exec.Query(ctx, cmd, &result, args...); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// QueryOne runs a singleton-returning query on exec and returns its element.
+// If the query executes successfully but doesn't return a result
+// a NoDataError is returned.
+func QueryOne[T any](
+	ctx context.Context,
+	exec Executor,
+	cmd string,
+	args ...any,
+) (T, error) {
+	var result T
+	if err := exec.QuerySingle(ctx, cmd, &result, args...); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return result, nil
+}
+
+// QueryOptional runs a singleton-returning query on exec and returns its
+// element. The returned bool is false if the query didn't return a result.
+// If T is an optional type like OptionalStr, Opt[string] or *string
+// the bool is also false if the result's value is missing.
+func QueryOptional[T any](
+	ctx context.Context,
+	exec Executor,
+	cmd string,
+	args ...any,
+) (T, bool, error) {
+	var result T
+	err := exec.QuerySingle(ctx, cmd, &result, args...)
+
+	var edbErr Error
+	switch {
+	case err == nil:
+		return result, !isMissing(&result), nil
+	case errors.As(err, &edbErr) && edbErr.Category(NoDataError):
+		var zero T
+		return zero, false, nil
+	default:
+		var zero T
+		return zero, false, err
+	}
+}
+
+// isMissing returns true if out points to a nil pointer, an optional type
+// with a Get() (T, bool) method whose value is missing or a struct that
+// embeds Optional and is missing.
+func isMissing(out any) bool {
+	if in, ok := out.(interface{ Missing() bool }); ok {
+		return in.Missing()
+	}
+
+	val := reflect.ValueOf(out)
+	if elem := val.Elem(); elem.Kind() == reflect.Pointer {
+		return elem.IsNil()
+	}
+
+	get := val.MethodByName("Get")
+	if !get.IsValid() {
+		return false
+	}
+
+	typ := get.Type()
+	if typ.NumIn() != 0 ||
+		typ.NumOut() != 2 ||
+		typ.Out(1).Kind() != reflect.Bool {
+		return false
+	}
+
+	return !get.Call(nil)[1].Bool()
+}
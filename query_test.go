@@ -0,0 +1,170 @@
+// This source file is part of the EdgeDB open source project.
+//
+// Copyright EdgeDB Inc. and the EdgeDB authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edgedb_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	edgedb "github.com/edgedb/edgedb-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// QueryAll, QueryOne and QueryOptional return their results instead of
+// decoding them into an out argument. They accept any Executor so they can
+// be used with both *Client and *Tx.
+func ExampleQueryAll() {
+	type User struct {
+		ID   edgedb.UUID `edgedb:"id"`
+		Name string      `edgedb:"name"`
+	}
+
+	users, err := edgedb.QueryAll[User](ctx, client, "SELECT User {name}")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = client.Tx(ctx, func(ctx context.Context, tx *edgedb.Tx) error {
+		user, ok, e := edgedb.QueryOptional[User](
+			ctx,
+			tx,
+			"SELECT User {name} FILTER .name = <str>$0",
+			users[0].Name,
+		)
+		if e != nil || !ok {
+			return e
+		}
+
+		count, e := edgedb.QueryOne[int64](
+			ctx,
+			tx,
+			"SELECT count(User FILTER .name = <str>$0)",
+			user.Name,
+		)
+		log.Println(count)
+		return e
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type noDataError struct{}
+
+func (noDataError) Error() string { return "edgedb.NoDataError: zero results" }
+
+func (noDataError) Unwrap() error { return nil }
+
+func (noDataError) HasTag(edgedb.ErrorTag) bool { return false }
+
+func (noDataError) Category(c edgedb.ErrorCategory) bool {
+	return c == edgedb.NoDataError
+}
+
+// singleExecutor is an Executor that sets QuerySingle's out argument
+// to result if it is not nil and returns err.
+type singleExecutor struct {
+	edgedb.Executor
+	result any
+	err    error
+}
+
+func (e singleExecutor) QuerySingle(
+	_ context.Context,
+	_ string,
+	out any,
+	_ ...any,
+) error {
+	if e.result != nil {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(e.result))
+	}
+
+	return e.err
+}
+
+func TestQueryOptional(t *testing.T) {
+	ctx := context.Background()
+
+	str, ok, err := edgedb.QueryOptional[string](
+		ctx, singleExecutor{result: "abc"}, "SELECT 'abc'")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "abc", str)
+
+	str, ok, err = edgedb.QueryOptional[string](
+		ctx, singleExecutor{err: noDataError{}}, "SELECT <str>{}")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "", str)
+
+	_, ok, err = edgedb.QueryOptional[string](
+		ctx, singleExecutor{err: errors.New("bad")}, "SELECT 'abc'")
+	assert.EqualError(t, err, "bad")
+	assert.False(t, ok)
+}
+
+func TestQueryOptionalMissingValue(t *testing.T) {
+	ctx := context.Background()
+
+	// optional out values are set to missing instead of returning
+	// a NoDataError
+	optional, ok, err := edgedb.QueryOptional[edgedb.OptionalStr](
+		ctx, singleExecutor{}, "SELECT <str>{}")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, edgedb.OptionalStr{}, optional)
+
+	optional, ok, err = edgedb.QueryOptional[edgedb.OptionalStr](
+		ctx,
+		singleExecutor{result: edgedb.NewOptionalStr("abc")},
+		"SELECT 'abc'",
+	)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, edgedb.NewOptionalStr("abc"), optional)
+
+	_, ok, err = edgedb.QueryOptional[edgedb.OptionalDateTime](
+		ctx, singleExecutor{}, "SELECT <datetime>{}")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = edgedb.QueryOptional[edgedb.Opt[int64]](
+		ctx, singleExecutor{}, "SELECT <int64>{}")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = edgedb.QueryOptional[*string](
+		ctx, singleExecutor{}, "SELECT <str>{}")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	type User struct {
+		edgedb.Optional
+		Name string `edgedb:"name"`
+	}
+
+	var user User
+	user.SetMissing(true)
+	_, ok, err = edgedb.QueryOptional[User](
+		ctx, singleExecutor{result: user}, "SELECT User { name } LIMIT 1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}